@@ -0,0 +1,162 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/isalikov/radio-record-cli/internal/api"
+	"github.com/isalikov/radio-record-cli/internal/config"
+)
+
+const favUsage = `Использование:
+  radio-record fav export [--format json|m3u] [файл]   Экспорт избранного (по умолчанию в stdout)
+  radio-record fav import [--format json|m3u] файл     Импорт избранного
+  radio-record fav sync [каталог]                      Синхронизация через каталог (например, git-репозиторий dotfiles)
+  radio-record fav sync --off                          Отключить синхронизацию
+`
+
+// runFav handles the `fav` subcommand and returns the exit code
+func runFav(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, favUsage)
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка загрузки конфига: %v\n", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "export":
+		err = favExport(cfg, args[1:])
+	case "import":
+		err = favImport(cfg, args[1:])
+	case "sync":
+		err = favSync(cfg, args[1:])
+	default:
+		fmt.Fprint(os.Stderr, favUsage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func favExport(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("fav export", flag.ContinueOnError)
+	format := fs.String("format", "", "формат: json или m3u")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Формат проверяется до создания файла, чтобы не затереть его зря
+	path := fs.Arg(0)
+	if *format == "" {
+		*format = config.FormatFromPath(path)
+	}
+	if *format != config.FormatJSON && *format != config.FormatM3U {
+		return fmt.Errorf("неизвестный формат %q", *format)
+	}
+
+	stations, err := api.NewClient().GetStations()
+	if err != nil {
+		return fmt.Errorf("не удалось загрузить станции: %w", err)
+	}
+
+	if path == "" {
+		return cfg.ExportFavorites(os.Stdout, *format, stations)
+	}
+
+	f, err := os.Create(config.ExpandHome(path))
+	if err != nil {
+		return err
+	}
+	if err := cfg.ExportFavorites(f, *format, stations); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func favImport(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("fav import", flag.ContinueOnError)
+	format := fs.String("format", "", "формат: json или m3u")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := fs.Arg(0)
+	if path == "" {
+		return fmt.Errorf("не указан файл для импорта")
+	}
+
+	f, err := os.Open(config.ExpandHome(path))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if *format == "" {
+		*format = config.FormatFromPath(path)
+	}
+
+	stations, err := api.NewClient().GetStations()
+	if err != nil {
+		return fmt.Errorf("не удалось загрузить станции: %w", err)
+	}
+
+	added, missing, err := cfg.ImportFavorites(f, *format, stations)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range missing {
+		name := e.Title
+		if name == "" {
+			name = e.Stream
+		}
+		fmt.Fprintf(os.Stderr, "Не найдена станция: %s\n", name)
+	}
+	fmt.Printf("Добавлено в избранное: %d\n", added)
+
+	return cfg.Save()
+}
+
+func favSync(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("fav sync", flag.ContinueOnError)
+	off := fs.Bool("off", false, "отключить синхронизацию")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *off {
+		cfg.SyncDir = ""
+		fmt.Println("Синхронизация отключена")
+		return cfg.Save()
+	}
+
+	if dir := fs.Arg(0); dir != "" {
+		abs, err := filepath.Abs(config.ExpandHome(dir))
+		if err != nil {
+			return err
+		}
+		cfg.SyncDir = abs
+	}
+
+	if cfg.SyncDir == "" {
+		return fmt.Errorf("каталог синхронизации не задан")
+	}
+
+	if err := cfg.Sync(); err != nil {
+		return err
+	}
+	fmt.Printf("Синхронизировано с %s\n", cfg.SyncPath())
+	return nil
+}
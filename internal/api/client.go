@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -15,6 +16,39 @@ const (
 type Client struct {
 	http    *http.Client
 	baseURL string
+	observe RequestObserver
+}
+
+// RequestObserver is called after every API request with the endpoint name
+// ("stations", "history"), its duration and the error if it failed
+type RequestObserver func(endpoint string, duration time.Duration, err error)
+
+// Observe sets a hook for request metrics. Not safe to call while
+// requests are in flight.
+func (c *Client) Observe(fn RequestObserver) {
+	c.observe = fn
+}
+
+// do sends the request and decodes the JSON response into v
+func (c *Client) do(req *http.Request, endpoint string, v interface{}) (err error) {
+	if c.observe != nil {
+		start := time.Now()
+		defer func() { c.observe(endpoint, time.Since(start), err) }()
+	}
+
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%s: %s", endpoint, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
 }
 
 func NewClient() *Client {
@@ -40,6 +74,35 @@ type Station struct {
 	Genres     []Genre  `json:"genre"`
 }
 
+// Stream qualities
+const (
+	Quality64  = "64"
+	Quality128 = "128"
+	Quality320 = "320"
+	QualityHLS = "hls"
+)
+
+// Qualities lists stream qualities from best to smallest
+var Qualities = []string{Quality320, Quality128, Quality64, QualityHLS}
+
+// StreamURL returns the stream of the given quality, falling back to
+// 320 kbps when the station has no such stream
+func (s Station) StreamURL(quality string) string {
+	var url string
+	switch quality {
+	case Quality64:
+		url = s.Stream64
+	case Quality128:
+		url = s.Stream128
+	case QualityHLS:
+		url = s.StreamHLS
+	}
+	if url == "" {
+		url = s.Stream320
+	}
+	return url
+}
+
 type Genre struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
@@ -69,50 +132,63 @@ type historyResponse struct {
 
 // GetStations fetches all available radio stations
 func (c *Client) GetStations() ([]Station, error) {
-	req, err := http.NewRequest("GET", c.baseURL+"/stations/", nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("User-Agent", userAgent)
+	stations, _, err := c.GetStationsWithGenres()
+	return stations, err
+}
 
-	resp, err := c.http.Do(req)
+// GetStationsWithGenres fetches all stations and the genre list in the
+// order the site shows it
+func (c *Client) GetStationsWithGenres() ([]Station, []Genre, error) {
+	req, err := http.NewRequest("GET", c.baseURL+"/stations/", nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	defer resp.Body.Close()
 
 	var result stationsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	if err := c.do(req, "stations", &result); err != nil {
+		return nil, nil, err
 	}
 
-	return result.Result.Stations, nil
+	return result.Result.Stations, result.Result.Genres, nil
 }
 
 // GetNowPlaying fetches current track for a station
 func (c *Client) GetNowPlaying(stationID int) (*Track, error) {
-	url := fmt.Sprintf("%s/station/history/?id=%d", c.baseURL, stationID)
+	return c.GetNowPlayingContext(context.Background(), stationID)
+}
 
-	req, err := http.NewRequest("GET", url, nil)
+// GetNowPlayingContext is like GetNowPlaying but the request is cancelled with ctx
+func (c *Client) GetNowPlayingContext(ctx context.Context, stationID int) (*Track, error) {
+	history, err := c.GetHistoryContext(ctx, stationID)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("User-Agent", userAgent)
 
-	resp, err := c.http.Do(req)
+	if len(history) == 0 {
+		return nil, nil
+	}
+
+	return &history[0], nil
+}
+
+// GetHistory fetches recently played tracks of a station, newest first
+func (c *Client) GetHistory(stationID int) ([]Track, error) {
+	return c.GetHistoryContext(context.Background(), stationID)
+}
+
+// GetHistoryContext is like GetHistory but the request is cancelled with ctx
+func (c *Client) GetHistoryContext(ctx context.Context, stationID int) ([]Track, error) {
+	url := fmt.Sprintf("%s/station/history/?id=%d", c.baseURL, stationID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
 	var result historyResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := c.do(req, "history", &result); err != nil {
 		return nil, err
 	}
 
-	if len(result.Result.History) == 0 {
-		return nil, nil
-	}
-
-	return &result.Result.History[0], nil
+	return result.Result.History, nil
 }
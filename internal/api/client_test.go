@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestGetStations(t *testing.T) {
@@ -69,6 +70,15 @@ func TestGetStations(t *testing.T) {
 	if stations[0].ID != 1 {
 		t.Errorf("Expected ID 1, got %d", stations[0].ID)
 	}
+
+	_, genres, err := client.GetStationsWithGenres()
+	if err != nil {
+		t.Fatalf("GetStationsWithGenres failed: %v", err)
+	}
+
+	if len(genres) != 2 || genres[0].Name != "HOUSE" || genres[1].Name != "TECHNO" {
+		t.Errorf("Expected genres in API order, got %v", genres)
+	}
 }
 
 func TestGetNowPlaying(t *testing.T) {
@@ -116,6 +126,48 @@ func TestGetNowPlaying(t *testing.T) {
 	}
 }
 
+func TestGetHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := r.URL.Query().Get("id"); id != "7" {
+			t.Errorf("Expected id=7, got %s", id)
+		}
+
+		response := historyResponse{
+			Result: struct {
+				History []Track `json:"history"`
+			}{
+				History: []Track{
+					{ID: 3, Artist: "Newest", Song: "Three"},
+					{ID: 2, Artist: "Older", Song: "Two"},
+					{ID: 1, Artist: "Oldest", Song: "One"},
+				},
+			},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		http:    server.Client(),
+		baseURL: server.URL,
+	}
+
+	history, err := client.GetHistory(7)
+	if err != nil {
+		t.Fatalf("GetHistory failed: %v", err)
+	}
+
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 tracks, got %d", len(history))
+	}
+
+	if history[0].Artist != "Newest" || history[2].Artist != "Oldest" {
+		t.Errorf("Expected newest first, got %v", history)
+	}
+}
+
 func TestGetNowPlayingEmpty(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		response := historyResponse{
@@ -145,3 +197,52 @@ func TestGetNowPlayingEmpty(t *testing.T) {
 		t.Errorf("Expected nil track for empty history, got %v", track)
 	}
 }
+
+func TestObserve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		http:    server.Client(),
+		baseURL: server.URL,
+	}
+
+	var endpoints []string
+	var failed int
+	client.Observe(func(endpoint string, duration time.Duration, err error) {
+		endpoints = append(endpoints, endpoint)
+		if err != nil {
+			failed++
+		}
+	})
+
+	if _, err := client.GetStations(); err == nil {
+		t.Error("Expected error for 503 response")
+	}
+	client.GetNowPlaying(1)
+
+	if len(endpoints) != 2 || endpoints[0] != "stations" || endpoints[1] != "history" {
+		t.Errorf("Expected stations and history requests, got %v", endpoints)
+	}
+	if failed != 2 {
+		t.Errorf("Expected 2 failed requests, got %d", failed)
+	}
+}
+
+func TestStreamURL(t *testing.T) {
+	s := Station{Stream64: "64", Stream320: "320"}
+
+	tests := map[string]string{
+		Quality64:  "64",
+		Quality128: "320", // No 128 stream, falls back to 320
+		Quality320: "320",
+		"":         "320",
+	}
+	for quality, expected := range tests {
+		if got := s.StreamURL(quality); got != expected {
+			t.Errorf("StreamURL(%q): expected %s, got %s", quality, expected, got)
+		}
+	}
+}
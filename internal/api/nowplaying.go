@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NowPlayingCache fetches current tracks for many stations with a bounded
+// worker pool and keeps results for a short time, so that browsing all
+// stations does not hammer the API.
+type NowPlayingCache struct {
+	client  *Client
+	ttl     time.Duration
+	workers int
+
+	mu      sync.Mutex
+	entries map[int]nowPlayingEntry
+}
+
+type nowPlayingEntry struct {
+	track   *Track
+	fetched time.Time
+}
+
+func NewNowPlayingCache(client *Client, ttl time.Duration, workers int) *NowPlayingCache {
+	if workers < 1 {
+		workers = 1
+	}
+	return &NowPlayingCache{
+		client:  client,
+		ttl:     ttl,
+		workers: workers,
+		entries: make(map[int]nowPlayingEntry),
+	}
+}
+
+// Get returns a cached track for a station. The second value is false
+// if the station was never fetched or the entry has expired.
+func (c *NowPlayingCache) Get(stationID int) (*Track, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[stationID]
+	if !ok || time.Since(e.fetched) > c.ttl {
+		return e.track, false
+	}
+	return e.track, true
+}
+
+// Fetch returns current tracks for the given stations, requesting only
+// stations whose cached entry has expired. Stations without a track or
+// with a failed request are absent from the result. Cancelling ctx aborts
+// requests in flight and stops issuing new ones.
+func (c *NowPlayingCache) Fetch(ctx context.Context, stationIDs []int) map[int]*Track {
+	result := make(map[int]*Track, len(stationIDs))
+	var stale []int
+
+	c.mu.Lock()
+	for _, id := range stationIDs {
+		e, ok := c.entries[id]
+		if ok && time.Since(e.fetched) <= c.ttl {
+			if e.track != nil {
+				result[id] = e.track
+			}
+			continue
+		}
+		stale = append(stale, id)
+	}
+	c.mu.Unlock()
+
+	if len(stale) == 0 {
+		return result
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var resultMu sync.Mutex
+
+	workers := c.workers
+	if workers > len(stale) {
+		workers = len(stale)
+	}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				track, err := c.client.GetNowPlayingContext(ctx, id)
+				if err != nil {
+					continue
+				}
+
+				c.mu.Lock()
+				c.entries[id] = nowPlayingEntry{track: track, fetched: time.Now()}
+				c.mu.Unlock()
+
+				if track != nil {
+					resultMu.Lock()
+					result[id] = track
+					resultMu.Unlock()
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, id := range stale {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case jobs <- id:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return result
+}
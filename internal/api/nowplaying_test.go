@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNowPlayingCacheFetch(t *testing.T) {
+	var requests, inFlight, maxInFlight int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		id, _ := strconv.Atoi(r.URL.Query().Get("id"))
+		var history []Track
+		if id != 3 { // station 3 has empty history
+			history = []Track{{ID: id * 100, Artist: fmt.Sprintf("Artist %d", id)}}
+		}
+
+		response := historyResponse{}
+		response.Result.History = history
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := &Client{http: server.Client(), baseURL: server.URL}
+	cache := NewNowPlayingCache(client, time.Minute, 2)
+
+	ids := []int{1, 2, 3, 4, 5}
+	tracks := cache.Fetch(context.Background(), ids)
+
+	if len(tracks) != 4 {
+		t.Errorf("Expected 4 tracks, got %d", len(tracks))
+	}
+	if tracks[2] == nil || tracks[2].Artist != "Artist 2" {
+		t.Errorf("Expected track for station 2, got %v", tracks[2])
+	}
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", maxInFlight)
+	}
+
+	// Second call is served from cache
+	cache.Fetch(context.Background(), ids)
+	if requests != 5 {
+		t.Errorf("Expected 5 requests in total, got %d", requests)
+	}
+
+	if track, ok := cache.Get(1); !ok || track.ID != 100 {
+		t.Errorf("Expected cached track for station 1, got %v (ok=%v)", track, ok)
+	}
+}
+
+func TestNowPlayingCacheCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("No requests expected after cancel")
+	}))
+	defer server.Close()
+
+	client := &Client{http: server.Client(), baseURL: server.URL}
+	cache := NewNowPlayingCache(client, time.Minute, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if tracks := cache.Fetch(ctx, []int{1, 2}); len(tracks) != 0 {
+		t.Errorf("Expected no tracks, got %d", len(tracks))
+	}
+}
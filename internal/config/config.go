@@ -1,15 +1,103 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"time"
 )
 
+// syncFileName is the name of the config copy kept in Config.SyncDir
+const syncFileName = "radio-record-cli.json"
+
 type Config struct {
-	Favorites []int `json:"favorites"` // Station IDs
-	Volume    int   `json:"volume"`
-	path      string
+	Favorites   []int          `json:"favorites"` // Station IDs
+	Volume      int            `json:"volume"`
+	ShowOnAir   bool           `json:"show_on_air"`               // Column with current tracks in the station list
+	Sort        string         `json:"sort,omitempty"`            // Station list order: api, title, genre, recent or plays
+	Genres      Genres         `json:"genre_filter"`              // Genre filter of the station list
+	Quality     string         `json:"quality,omitempty"`         // Stream quality: 320 (default), 128, 64 or hls
+	Autoplay    bool           `json:"autoplay_last"`             // Play the last station on startup
+	Session     Session        `json:"session"`                   // Restored on startup
+	Recent      []int          `json:"recent,omitempty"`          // Recently played station IDs, newest first
+	History     []string       `json:"command_history,omitempty"` // Command palette history, oldest first
+	Equalizer   string         `json:"equalizer,omitempty"`       // Audio filter preset for all stations
+	StationEQ   map[int]string `json:"station_eq,omitempty"`      // Preset by station ID, overrides Equalizer
+	EQPresets   []Equalizer    `json:"eq_presets,omitempty"`      // Custom presets
+	AudioDevice string         `json:"audio_device,omitempty"`    // mpv audio output, mpv chooses if empty
+	Crossfade   float64        `json:"crossfade,omitempty"`       // Seconds of fade between stations and on start, 0 disables
+	Visualizer  bool           `json:"visualizer"`                // Spectrum bars in the now-playing box
+	AlbumArt    string         `json:"album_art,omitempty"`       // auto, kitty, iterm2, sixel, blocks or off
+	Links       []Link         `json:"links,omitempty"`           // Music service links in the now-playing box
+	Notify      bool           `json:"notifications"`             // Desktop notifications on track change
+	HTTP        HTTP           `json:"http"`                      // Local control API and web remote
+	Hooks       []Hook         `json:"hooks,omitempty"`           // Commands and webhooks run on player events
+	SyncDir     string         `json:"sync_dir,omitempty"`        // Directory with a shared copy (e.g. dotfiles repo)
+	UpdatedAt   time.Time      `json:"updated_at,omitempty"`      // Used for last-writer-wins merge with SyncDir
+	path        string
+	saved       []byte // Shared settings as last loaded or written, see Save
+}
+
+// Link is a music service search link. {query}, {artist} and {song} in URL
+// are replaced with URL-escaped values of the current track.
+type Link struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// DefaultHTTPAddr is the default address of the control API: localhost only
+const DefaultHTTPAddr = "127.0.0.1:8765"
+
+// HTTP configures the local control API
+type HTTP struct {
+	Enabled bool   `json:"enabled"`
+	Addr    string `json:"addr,omitempty"`  // host:port, DefaultHTTPAddr if empty
+	Token   string `json:"token,omitempty"` // Generated on first start if empty
+}
+
+// Session is the UI state of the last run
+type Session struct {
+	Station       int  `json:"station,omitempty"` // Last played station ID
+	Cursor        int  `json:"cursor,omitempty"`  // ID of the station under the cursor
+	FavoritesOnly bool `json:"favorites_only,omitempty"`
+}
+
+// Genres is a genre filter. A single genre is a tab in the UI, several
+// are selected in the genre picker.
+type Genres struct {
+	Names []string `json:"names,omitempty"`
+	All   bool     `json:"all,omitempty"` // Stations must have all genres (AND), not any of them (OR)
+}
+
+// Hook runs a command and/or posts to a webhook on player events
+type Hook struct {
+	Events  []string `json:"events,omitempty"`  // play, stop, track_change, volume_change, error; all if empty
+	Command string   `json:"command,omitempty"` // Shell command, gets event data in env and JSON on stdin
+	URL     string   `json:"url,omitempty"`     // Webhook, gets event JSON in a POST body
+	Timeout int      `json:"timeout,omitempty"` // Seconds per attempt, 10 if zero
+	Retries int      `json:"retries,omitempty"` // Extra webhook attempts on failure, 2 if zero, -1 disables
+
+	// Chat webhooks post formatted track changes instead of raw JSON
+	Format   string `json:"format,omitempty"`   // json (default), slack, mattermost or telegram
+	Template string `json:"template,omitempty"` // Message with {station}, {artist}, {song}, {track} placeholders
+	ChatID   string `json:"chat_id,omitempty"`  // Telegram chat ID, URL is https://api.telegram.org/bot<token>
+	Station  string `json:"station,omitempty"`  // Only events of the station with this prefix
+}
+
+// DefaultLinks are used when the config has no links
+var DefaultLinks = []Link{
+	{Name: "YT Music", URL: "https://music.youtube.com/search?q={query}"},
+	{Name: "Yandex", URL: "https://music.yandex.ru/search?text={query}"},
+	{Name: "Spotify", URL: "https://open.spotify.com/search/{query}"},
+}
+
+// TrackLinks returns configured links or the defaults
+func (c *Config) TrackLinks() []Link {
+	if len(c.Links) == 0 {
+		return DefaultLinks
+	}
+	return c.Links
 }
 
 func Load() (*Config, error) {
@@ -33,21 +121,194 @@ func Load() (*Config, error) {
 
 	json.Unmarshal(data, cfg)
 	cfg.path = configPath
+	cfg.mergeSync()
+	cfg.saved = cfg.sharedJSON()
 	return cfg, nil
 }
 
+// shared is the part of Config kept in SyncDir. Secrets (the HTTP token,
+// webhook URLs and bot tokens in hooks) and machine-specific settings
+// (audio device, session, recent stations, command history) stay local:
+// the sync directory is usually a dotfiles repo.
+type shared struct {
+	Favorites  []int          `json:"favorites"`
+	Volume     int            `json:"volume"`
+	ShowOnAir  bool           `json:"show_on_air"`
+	Sort       string         `json:"sort,omitempty"`
+	Genres     Genres         `json:"genre_filter"`
+	Quality    string         `json:"quality,omitempty"`
+	Autoplay   bool           `json:"autoplay_last"`
+	Equalizer  string         `json:"equalizer,omitempty"`
+	StationEQ  map[int]string `json:"station_eq,omitempty"`
+	EQPresets  []Equalizer    `json:"eq_presets,omitempty"`
+	Crossfade  float64        `json:"crossfade,omitempty"`
+	Visualizer bool           `json:"visualizer"`
+	AlbumArt   string         `json:"album_art,omitempty"`
+	Links      []Link         `json:"links,omitempty"`
+	Notify     bool           `json:"notifications"`
+	UpdatedAt  time.Time      `json:"updated_at,omitempty"`
+}
+
+func (c *Config) shared() shared {
+	return shared{
+		Favorites:  c.Favorites,
+		Volume:     c.Volume,
+		ShowOnAir:  c.ShowOnAir,
+		Sort:       c.Sort,
+		Genres:     c.Genres,
+		Quality:    c.Quality,
+		Autoplay:   c.Autoplay,
+		Equalizer:  c.Equalizer,
+		StationEQ:  c.StationEQ,
+		EQPresets:  c.EQPresets,
+		Crossfade:  c.Crossfade,
+		Visualizer: c.Visualizer,
+		AlbumArt:   c.AlbumArt,
+		Links:      c.Links,
+		Notify:     c.Notify,
+		UpdatedAt:  c.UpdatedAt,
+	}
+}
+
+// sharedJSON returns the shared settings without UpdatedAt, to tell whether
+// they changed since the last load or write
+func (c *Config) sharedJSON() []byte {
+	s := c.shared()
+	s.UpdatedAt = time.Time{}
+	data, _ := json.Marshal(s)
+	return data
+}
+
+func (c *Config) applyShared(s shared) {
+	c.Favorites = s.Favorites
+	if c.Favorites == nil {
+		c.Favorites = []int{}
+	}
+	c.Volume = s.Volume
+	c.ShowOnAir = s.ShowOnAir
+	c.Sort = s.Sort
+	c.Genres = s.Genres
+	c.Quality = s.Quality
+	c.Autoplay = s.Autoplay
+	c.Equalizer = s.Equalizer
+	c.StationEQ = s.StationEQ
+	c.EQPresets = s.EQPresets
+	c.Crossfade = s.Crossfade
+	c.Visualizer = s.Visualizer
+	c.AlbumArt = s.AlbumArt
+	c.Links = s.Links
+	c.Notify = s.Notify
+	c.UpdatedAt = s.UpdatedAt
+}
+
+// mergeSync replaces the local shared settings with the copy from SyncDir
+// if that copy was written later (last writer wins).
+func (c *Config) mergeSync() {
+	if c.SyncDir == "" {
+		return
+	}
+
+	data, err := os.ReadFile(c.SyncPath())
+	if err != nil {
+		return
+	}
+
+	var remote shared
+	if err := json.Unmarshal(data, &remote); err != nil {
+		return
+	}
+	if !remote.UpdatedAt.After(c.UpdatedAt) {
+		return
+	}
+	c.applyShared(remote)
+}
+
+// SyncPath returns the path of the shared config copy, or "" if sync is disabled
+func (c *Config) SyncPath() string {
+	if c.SyncDir == "" {
+		return ""
+	}
+	return filepath.Join(c.SyncDir, syncFileName)
+}
+
+// Sync merges the shared copy into the local config and writes the result
+// back to both locations.
+func (c *Config) Sync() error {
+	c.mergeSync()
+	return c.write()
+}
+
+// Save writes the config. UpdatedAt only moves when shared settings
+// changed: otherwise a newer copy from SyncDir is merged first, so saving
+// on exit does not overwrite changes made on another machine.
 func (c *Config) Save() error {
-	dir := filepath.Dir(c.path)
+	if bytes.Equal(c.sharedJSON(), c.saved) {
+		c.mergeSync()
+	} else {
+		c.UpdatedAt = time.Now().UTC()
+	}
+	return c.write()
+}
+
+func (c *Config) write() error {
+	if err := writeJSON(c.path, c); err != nil {
+		return err
+	}
+	c.saved = c.sharedJSON()
+
+	if c.SyncDir == "" {
+		return nil
+	}
+	return writeJSON(c.SyncPath(), c.shared())
+}
+
+// writeJSON writes v readable by the owner only: the config holds the HTTP
+// API token and hook secrets. WriteFile keeps the mode of an existing file,
+// so files written by older versions are tightened first.
+func writeJSON(path string, v interface{}) error {
+	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
 
-	data, err := json.MarshalIndent(c, "", "  ")
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(c.path, data, 0644)
+	if err := os.Chmod(path, 0600); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// MaxRecent is the length of the recently played list
+const MaxRecent = 15
+
+// AddRecent moves the station to the front of the recently played list
+func (c *Config) AddRecent(stationID int) {
+	recent := []int{stationID}
+	for _, id := range c.Recent {
+		if id != stationID && len(recent) < MaxRecent {
+			recent = append(recent, id)
+		}
+	}
+	c.Recent = recent
+}
+
+// MaxHistory is the number of command palette lines kept in the config
+const MaxHistory = 50
+
+// AddHistory appends a command line to the palette history. Repeating the
+// last line does not add a duplicate.
+func (c *Config) AddHistory(line string) {
+	if n := len(c.History); n > 0 && c.History[n-1] == line {
+		return
+	}
+	c.History = append(c.History, line)
+	if len(c.History) > MaxHistory {
+		c.History = c.History[len(c.History)-MaxHistory:]
+	}
 }
 
 func (c *Config) IsFavorite(stationID int) bool {
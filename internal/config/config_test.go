@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 )
 
@@ -102,6 +104,32 @@ func TestSaveAndLoad(t *testing.T) {
 	_ = cfg2 // Suppress unused warning
 }
 
+func TestSaveKeepsConfigPrivate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no permission bits on Windows")
+	}
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := &Config{HTTP: HTTP{Token: "secret"}, path: configPath}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if info, err := os.Stat(configPath); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("Expected a new config with mode 0600, got %v", info.Mode().Perm())
+	}
+
+	// A config written by an older version is tightened on save
+	if err := os.Chmod(configPath, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if info, _ := os.Stat(configPath); info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the existing config tightened to 0600, got %v", info.Mode().Perm())
+	}
+}
+
 func TestLoadNonExistent(t *testing.T) {
 	// Save original function behavior - Load should return default config
 	// when file doesn't exist
@@ -134,3 +162,74 @@ func TestEmptyFavorites(t *testing.T) {
 		t.Error("Should be able to add favorite to empty list")
 	}
 }
+
+func TestAddRecent(t *testing.T) {
+	cfg := &Config{}
+
+	for id := 1; id <= MaxRecent+5; id++ {
+		cfg.AddRecent(id)
+	}
+	cfg.AddRecent(10)
+
+	if len(cfg.Recent) != MaxRecent {
+		t.Fatalf("Expected %d recent stations, got %d", MaxRecent, len(cfg.Recent))
+	}
+	if cfg.Recent[0] != 10 || cfg.Recent[1] != MaxRecent+5 {
+		t.Errorf("Expected 10 moved to the front, got %v", cfg.Recent)
+	}
+	for _, id := range cfg.Recent[1:] {
+		if id == 10 {
+			t.Errorf("Expected no duplicates, got %v", cfg.Recent)
+		}
+	}
+}
+
+func TestAddHistory(t *testing.T) {
+	cfg := &Config{}
+
+	for i := 0; i < MaxHistory+5; i++ {
+		cfg.AddHistory(fmt.Sprintf("vol %d", i))
+	}
+	cfg.AddHistory("play deep")
+	cfg.AddHistory("play deep")
+
+	if len(cfg.History) != MaxHistory {
+		t.Fatalf("Expected %d history lines, got %d", MaxHistory, len(cfg.History))
+	}
+	if last := cfg.History[MaxHistory-1]; last != "play deep" || cfg.History[MaxHistory-2] == last {
+		t.Errorf("Expected one \"play deep\" at the end, got %v", cfg.History[MaxHistory-3:])
+	}
+}
+
+func TestEqualizerFor(t *testing.T) {
+	cfg := &Config{Equalizer: "bass-cut"}
+
+	if eq := cfg.EqualizerFor(1); eq.Name != "bass-cut" || eq.Gains[0] != -12 {
+		t.Errorf("Expected the common bass-cut preset, got %+v", eq)
+	}
+
+	cfg.SetStationEqualizer(2, "mono")
+	if eq := cfg.EqualizerFor(2); !eq.Mono {
+		t.Errorf("Expected the station preset, got %+v", eq)
+	}
+	cfg.SetStationEqualizer(2, "")
+	if name := cfg.EqualizerName(2); name != "bass-cut" {
+		t.Errorf("Expected the common preset after reset, got %q", name)
+	}
+
+	// A custom preset replaces the built-in one with the same name, new ones go last
+	cfg.SaveEqualizer(Equalizer{Name: "bass-cut", Gains: []float64{-3}})
+	cfg.SaveEqualizer(Equalizer{Name: CustomEqualizer, Gains: []float64{1}})
+	presets := cfg.EqualizerPresets()
+	if len(presets) != len(DefaultEqualizers)+1 || presets[len(presets)-1].Name != CustomEqualizer {
+		t.Fatalf("Expected built-in presets and custom at the end, got %+v", presets)
+	}
+	if eq := cfg.EqualizerFor(1); eq.Gains[0] != -3 {
+		t.Errorf("Expected the overridden bass-cut, got %+v", eq)
+	}
+
+	cfg.Equalizer = "missing"
+	if eq := cfg.EqualizerFor(1); eq.Name != DefaultEqualizer {
+		t.Errorf("Expected flat for an unknown preset, got %+v", eq)
+	}
+}
@@ -0,0 +1,112 @@
+package config
+
+// Equalizer is an audio filter preset: gains of the 10-band equalizer,
+// loudness normalization and downmix to mono
+type Equalizer struct {
+	Name      string    `json:"name"`
+	Gains     []float64 `json:"gains,omitempty"`     // dB for 31, 62, 125, 250, 500 Hz, 1, 2, 4, 8, 16 kHz
+	Normalize string    `json:"normalize,omitempty"` // loudnorm or dynaudnorm
+	Mono      bool      `json:"mono,omitempty"`
+}
+
+// DefaultEqualizer is the preset used when none is selected
+const DefaultEqualizer = "flat"
+
+// CustomEqualizer is the preset the equalizer editor saves changes to
+const CustomEqualizer = "custom"
+
+// DefaultEqualizers are built-in presets. A preset in Config.EQPresets
+// with the same name replaces the built-in one.
+var DefaultEqualizers = []Equalizer{
+	{Name: DefaultEqualizer},
+	{Name: "bass-boost", Gains: []float64{6, 5, 4, 2, 0, 0, 0, 0, 0, 0}},
+	{Name: "bass-cut", Gains: []float64{-12, -9, -6, -3, 0, 0, 0, 0, 0, 0}},
+	{Name: "treble-boost", Gains: []float64{0, 0, 0, 0, 0, 0, 2, 4, 5, 6}},
+	{Name: "vocal", Gains: []float64{-3, -2, -1, 0, 2, 3, 3, 2, 0, -1}},
+	{Name: "loudness", Normalize: "dynaudnorm"},
+	{Name: "night", Gains: []float64{-6, -4, -2, 0, 0, 0, 0, 0, -2, -4}, Normalize: "loudnorm"},
+	{Name: "mono", Mono: true},
+}
+
+// EqualizerPresets returns built-in presets followed by custom ones
+func (c *Config) EqualizerPresets() []Equalizer {
+	presets := make([]Equalizer, 0, len(DefaultEqualizers)+len(c.EQPresets))
+	for _, eq := range DefaultEqualizers {
+		if custom, ok := c.findEqualizer(eq.Name); ok {
+			eq = custom
+		}
+		presets = append(presets, eq)
+	}
+	for _, eq := range c.EQPresets {
+		if !isDefaultEqualizer(eq.Name) {
+			presets = append(presets, eq)
+		}
+	}
+	return presets
+}
+
+// EqualizerName returns the preset name for a station: its own preset if
+// set, otherwise the common one
+func (c *Config) EqualizerName(stationID int) string {
+	if name, ok := c.StationEQ[stationID]; ok {
+		return name
+	}
+	if c.Equalizer != "" {
+		return c.Equalizer
+	}
+	return DefaultEqualizer
+}
+
+// EqualizerFor returns the preset for a station. Unknown names give the
+// flat preset.
+func (c *Config) EqualizerFor(stationID int) Equalizer {
+	name := c.EqualizerName(stationID)
+	for _, eq := range c.EqualizerPresets() {
+		if eq.Name == name {
+			return eq
+		}
+	}
+	return Equalizer{Name: DefaultEqualizer}
+}
+
+// SetStationEqualizer selects a preset for one station. An empty name
+// returns the station to the common preset.
+func (c *Config) SetStationEqualizer(stationID int, name string) {
+	if name == "" {
+		delete(c.StationEQ, stationID)
+		return
+	}
+	if c.StationEQ == nil {
+		c.StationEQ = map[int]string{}
+	}
+	c.StationEQ[stationID] = name
+}
+
+// SaveEqualizer adds a custom preset or replaces the one with the same name
+func (c *Config) SaveEqualizer(eq Equalizer) {
+	for i := range c.EQPresets {
+		if c.EQPresets[i].Name == eq.Name {
+			c.EQPresets[i] = eq
+			return
+		}
+	}
+	c.EQPresets = append(c.EQPresets, eq)
+}
+
+func (c *Config) findEqualizer(name string) (Equalizer, bool) {
+	for _, eq := range c.EQPresets {
+		if eq.Name == name {
+			return eq, true
+		}
+	}
+	return Equalizer{}, false
+}
+
+func isDefaultEqualizer(name string) bool {
+	for _, eq := range DefaultEqualizers {
+		if eq.Name == name {
+			return true
+		}
+	}
+	return false
+}
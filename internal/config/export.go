@@ -0,0 +1,238 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/isalikov/radio-record-cli/internal/api"
+)
+
+// Export formats
+const (
+	FormatJSON = "json"
+	FormatM3U  = "m3u"
+)
+
+// FavoriteEntry describes an exported favorite. Prefix and title are kept
+// alongside the ID so that imports survive station ID changes.
+type FavoriteEntry struct {
+	ID     int    `json:"id"`
+	Prefix string `json:"prefix"`
+	Title  string `json:"title"`
+	Stream string `json:"stream,omitempty"`
+}
+
+type favoritesFile struct {
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Favorites  []FavoriteEntry `json:"favorites"`
+}
+
+// ExpandHome replaces a leading ~ in path with the home directory
+func ExpandHome(path string) string {
+	if path == "~" || len(path) > 1 && path[:2] == "~/" {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
+
+// FormatFromPath guesses the export format from a file extension
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".m3u", ".m3u8":
+		return FormatM3U
+	default:
+		return FormatJSON
+	}
+}
+
+// FavoriteEntries resolves favorite IDs against the station list.
+// Favorites missing from the list are exported with ID only.
+func (c *Config) FavoriteEntries(stations []api.Station) []FavoriteEntry {
+	byID := make(map[int]api.Station, len(stations))
+	for _, s := range stations {
+		byID[s.ID] = s
+	}
+
+	entries := make([]FavoriteEntry, 0, len(c.Favorites))
+	for _, id := range c.Favorites {
+		entry := FavoriteEntry{ID: id}
+		if s, ok := byID[id]; ok {
+			entry.Prefix = s.Prefix
+			entry.Title = s.Title
+			entry.Stream = s.Stream320
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// ExportFavorites writes favorites in the given format
+func (c *Config) ExportFavorites(w io.Writer, format string, stations []api.Station) error {
+	entries := c.FavoriteEntries(stations)
+
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(favoritesFile{
+			Version:    1,
+			ExportedAt: time.Now().UTC(),
+			Favorites:  entries,
+		})
+
+	case FormatM3U:
+		bw := bufio.NewWriter(w)
+		fmt.Fprintln(bw, "#EXTM3U")
+		for _, e := range entries {
+			if e.Stream == "" {
+				continue
+			}
+			fmt.Fprintf(bw, "#EXTINF:-1 tvg-id=%q radio-record-id=\"%d\",%s\n", e.Prefix, e.ID, e.Title)
+			fmt.Fprintln(bw, e.Stream)
+		}
+		return bw.Flush()
+	}
+
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// ImportFavorites reads favorites in the given format and adds the matching
+// stations to the config. Returns the number of added favorites and entries
+// that did not match any station.
+func (c *Config) ImportFavorites(r io.Reader, format string, stations []api.Station) (int, []FavoriteEntry, error) {
+	var entries []FavoriteEntry
+	var err error
+
+	switch format {
+	case FormatJSON:
+		var f favoritesFile
+		err = json.NewDecoder(r).Decode(&f)
+		entries = f.Favorites
+	case FormatM3U:
+		entries, err = parseM3U(r)
+	default:
+		err = fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	added := 0
+	var missing []FavoriteEntry
+	for _, e := range entries {
+		s := matchStation(e, stations)
+		if s == nil {
+			missing = append(missing, e)
+			continue
+		}
+		if !c.IsFavorite(s.ID) {
+			c.Favorites = append(c.Favorites, s.ID)
+			added++
+		}
+	}
+
+	return added, missing, nil
+}
+
+// matchStation finds a station for an imported entry. Prefix is the most
+// stable identifier, then the stream URL, ID and title.
+func matchStation(e FavoriteEntry, stations []api.Station) *api.Station {
+	if e.Prefix != "" {
+		for i := range stations {
+			if stations[i].Prefix == e.Prefix {
+				return &stations[i]
+			}
+		}
+	}
+	if e.Stream != "" {
+		for i := range stations {
+			s := &stations[i]
+			if s.Stream64 == e.Stream || s.Stream128 == e.Stream || s.Stream320 == e.Stream || s.StreamHLS == e.Stream {
+				return s
+			}
+		}
+	}
+	if e.ID != 0 {
+		for i := range stations {
+			if stations[i].ID == e.ID {
+				return &stations[i]
+			}
+		}
+	}
+	if e.Title != "" {
+		for i := range stations {
+			if strings.EqualFold(stations[i].Title, e.Title) {
+				return &stations[i]
+			}
+		}
+	}
+	return nil
+}
+
+func parseM3U(r io.Reader) ([]FavoriteEntry, error) {
+	var entries []FavoriteEntry
+	var pending FavoriteEntry
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || line == "#EXTM3U":
+			continue
+		case strings.HasPrefix(line, "#EXTINF:"):
+			pending = parseExtinf(line)
+		case strings.HasPrefix(line, "#"):
+			continue
+		default:
+			pending.Stream = line
+			entries = append(entries, pending)
+			pending = FavoriteEntry{}
+		}
+	}
+
+	return entries, scanner.Err()
+}
+
+// parseExtinf parses `#EXTINF:-1 key="value" ...,Title`
+func parseExtinf(line string) FavoriteEntry {
+	var e FavoriteEntry
+
+	info := strings.TrimPrefix(line, "#EXTINF:")
+	attrs := info
+	inQuotes := false
+	for i, r := range info {
+		if r == '"' {
+			inQuotes = !inQuotes
+		}
+		if r == ',' && !inQuotes {
+			attrs = info[:i]
+			e.Title = strings.TrimSpace(info[i+1:])
+			break
+		}
+	}
+
+	for _, field := range strings.Fields(attrs) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		switch key {
+		case "tvg-id":
+			e.Prefix = value
+		case "radio-record-id":
+			fmt.Sscanf(value, "%d", &e.ID)
+		}
+	}
+
+	return e
+}
@@ -0,0 +1,254 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isalikov/radio-record-cli/internal/api"
+)
+
+var testStations = []api.Station{
+	{ID: 1, Prefix: "rr_main", Title: "Record", Stream320: "https://example.com/rr_main_320"},
+	{ID: 2, Prefix: "deep", Title: "Deep", Stream320: "https://example.com/deep_320"},
+	{ID: 3, Prefix: "chil", Title: "Chill-Out, Lounge", Stream320: "https://example.com/chil_320"},
+}
+
+func TestExportImportJSON(t *testing.T) {
+	cfg := &Config{Favorites: []int{2, 3}}
+
+	var buf bytes.Buffer
+	if err := cfg.ExportFavorites(&buf, FormatJSON, testStations); err != nil {
+		t.Fatalf("ExportFavorites failed: %v", err)
+	}
+
+	// Station IDs changed, prefixes stayed the same
+	renumbered := []api.Station{
+		{ID: 20, Prefix: "deep", Title: "Deep"},
+		{ID: 30, Prefix: "chil", Title: "Chill-Out, Lounge"},
+	}
+
+	imported := &Config{Favorites: []int{}}
+	added, missing, err := imported.ImportFavorites(&buf, FormatJSON, renumbered)
+	if err != nil {
+		t.Fatalf("ImportFavorites failed: %v", err)
+	}
+
+	if added != 2 || len(missing) != 0 {
+		t.Errorf("Expected 2 added and 0 missing, got %d and %d", added, len(missing))
+	}
+	if !imported.IsFavorite(20) || !imported.IsFavorite(30) {
+		t.Errorf("Expected favorites [20 30], got %v", imported.Favorites)
+	}
+}
+
+func TestExportImportM3U(t *testing.T) {
+	cfg := &Config{Favorites: []int{1, 3}}
+
+	var buf bytes.Buffer
+	if err := cfg.ExportFavorites(&buf, FormatM3U, testStations); err != nil {
+		t.Fatalf("ExportFavorites failed: %v", err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "#EXTM3U\n") {
+		t.Errorf("Expected M3U header, got %q", buf.String())
+	}
+
+	imported := &Config{Favorites: []int{1}}
+	added, _, err := imported.ImportFavorites(&buf, FormatM3U, testStations)
+	if err != nil {
+		t.Fatalf("ImportFavorites failed: %v", err)
+	}
+
+	if added != 1 {
+		t.Errorf("Expected 1 added favorite, got %d", added)
+	}
+	if len(imported.Favorites) != 2 || imported.Favorites[1] != 3 {
+		t.Errorf("Expected favorites [1 3], got %v", imported.Favorites)
+	}
+}
+
+func TestImportM3UByStream(t *testing.T) {
+	playlist := "#EXTM3U\n#EXTINF:-1,Unknown\nhttps://example.com/deep_320\nhttps://example.com/missing\n"
+
+	cfg := &Config{Favorites: []int{}}
+	added, missing, err := cfg.ImportFavorites(strings.NewReader(playlist), FormatM3U, testStations)
+	if err != nil {
+		t.Fatalf("ImportFavorites failed: %v", err)
+	}
+
+	if added != 1 || !cfg.IsFavorite(2) {
+		t.Errorf("Expected station 2 imported, got %v", cfg.Favorites)
+	}
+	if len(missing) != 1 || missing[0].Stream != "https://example.com/missing" {
+		t.Errorf("Expected one missing entry, got %v", missing)
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := map[string]string{
+		"favorites.json": FormatJSON,
+		"favorites.M3U":  FormatM3U,
+		"list.m3u8":      FormatM3U,
+		"favorites":      FormatJSON,
+	}
+
+	for path, expected := range tests {
+		if got := FormatFromPath(path); got != expected {
+			t.Errorf("FormatFromPath(%q) = %q, expected %q", path, got, expected)
+		}
+	}
+}
+
+func TestSyncLastWriterWins(t *testing.T) {
+	tmpDir := t.TempDir()
+	syncDir := filepath.Join(tmpDir, "dotfiles")
+
+	local := &Config{
+		Favorites: []int{1},
+		Volume:    50,
+		SyncDir:   syncDir,
+		path:      filepath.Join(tmpDir, "local", "config.json"),
+	}
+	if err := local.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Another machine writes a newer copy
+	other := &Config{
+		Favorites: []int{1, 2},
+		Volume:    30,
+		SyncDir:   syncDir,
+		path:      filepath.Join(tmpDir, "other", "config.json"),
+	}
+	if err := other.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if err := local.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if local.Volume != 30 || len(local.Favorites) != 2 {
+		t.Errorf("Expected newer shared copy to win, got %+v", local)
+	}
+	if local.path != filepath.Join(tmpDir, "local", "config.json") {
+		t.Errorf("Expected local path to be kept, got %s", local.path)
+	}
+
+	// Older shared copy must not override local changes
+	local.Volume = 90
+	local.UpdatedAt = time.Now().Add(time.Hour)
+	local.mergeSync()
+	if local.Volume != 90 {
+		t.Errorf("Expected local volume 90 to win, got %d", local.Volume)
+	}
+
+	data, err := os.ReadFile(filepath.Join(syncDir, syncFileName))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if strings.Contains(string(data), "sync_dir") {
+		t.Errorf("Shared copy should not contain sync_dir: %s", data)
+	}
+}
+
+func TestSaveKeepsNewerSyncCopy(t *testing.T) {
+	tmpDir := t.TempDir()
+	syncDir := filepath.Join(tmpDir, "dotfiles")
+
+	local := &Config{
+		Favorites: []int{1},
+		Volume:    50,
+		SyncDir:   syncDir,
+		path:      filepath.Join(tmpDir, "local", "config.json"),
+	}
+	if err := local.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	updated := local.UpdatedAt
+
+	// Another machine changes the shared copy while this one keeps running
+	other := &Config{
+		Favorites: []int{1, 2},
+		Volume:    30,
+		SyncDir:   syncDir,
+		path:      filepath.Join(tmpDir, "other", "config.json"),
+	}
+	if err := other.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Saving on exit without shared changes picks up the newer copy
+	local.Session.Station = 7
+	if err := local.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if local.UpdatedAt.Equal(updated) || local.Volume != 30 || len(local.Favorites) != 2 {
+		t.Errorf("Expected the newer shared copy kept, got %+v", local)
+	}
+	data, err := os.ReadFile(filepath.Join(syncDir, syncFileName))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"volume": 30`) {
+		t.Errorf("Expected the shared copy untouched, got %s", data)
+	}
+
+	// A shared change moves UpdatedAt
+	updated = local.UpdatedAt
+	local.Volume = 40
+	if err := local.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if !local.UpdatedAt.After(updated) {
+		t.Errorf("Expected UpdatedAt bumped after a shared change")
+	}
+}
+
+func TestSyncKeepsSecretsLocal(t *testing.T) {
+	tmpDir := t.TempDir()
+	syncDir := filepath.Join(tmpDir, "dotfiles")
+
+	local := &Config{
+		Favorites:   []int{1},
+		HTTP:        HTTP{Enabled: true, Token: "secret-token"},
+		Hooks:       []Hook{{URL: "https://api.telegram.org/bot123:ABC", ChatID: "42"}},
+		AudioDevice: "pulse/usb-headset",
+		History:     []string{"play deep"},
+		SyncDir:     syncDir,
+		path:        filepath.Join(tmpDir, "local", "config.json"),
+	}
+	if err := local.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(syncDir, syncFileName))
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	for _, secret := range []string{"secret-token", "bot123", "pulse/usb-headset", "play deep", `"http"`, `"hooks"`} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Shared copy should not contain %s: %s", secret, data)
+		}
+	}
+
+	// A newer copy from another machine replaces shared settings only
+	other := &Config{
+		Favorites: []int{1, 2},
+		SyncDir:   syncDir,
+		path:      filepath.Join(tmpDir, "other", "config.json"),
+	}
+	if err := other.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	local.mergeSync()
+	if len(local.Favorites) != 2 {
+		t.Errorf("Expected favorites from the shared copy, got %v", local.Favorites)
+	}
+	if local.HTTP.Token != "secret-token" || len(local.Hooks) != 1 || local.AudioDevice != "pulse/usb-headset" {
+		t.Errorf("Expected local secrets and device kept, got %+v", local)
+	}
+}
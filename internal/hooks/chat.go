@@ -0,0 +1,91 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/isalikov/radio-record-cli/internal/config"
+	"github.com/isalikov/radio-record-cli/internal/state"
+)
+
+// Webhook formats
+const (
+	FormatJSON       = "json"
+	FormatSlack      = "slack"
+	FormatMattermost = "mattermost"
+	FormatTelegram   = "telegram"
+)
+
+// DefaultTemplate is the chat message when the hook has no template
+const DefaultTemplate = "🎵 {track} · {station}"
+
+// isChat reports whether the hook posts formatted chat messages
+func isChat(h config.Hook) bool {
+	return h.Format != "" && h.Format != FormatJSON
+}
+
+// message renders the chat message of the hook for an event
+func message(h config.Hook, p Payload) string {
+	template := h.Template
+	if template == "" {
+		template = DefaultTemplate
+	}
+	return p.State.Format(template)
+}
+
+// webhookRequest returns the URL and the body to post for an event
+func webhookRequest(h config.Hook, p Payload) (string, []byte, error) {
+	var v interface{}
+	url := h.URL
+	text := message(h, p)
+	image := p.State.Image
+
+	switch h.Format {
+	case "", FormatJSON:
+		v = p
+
+	case FormatSlack:
+		block := map[string]interface{}{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": text},
+		}
+		if image != "" {
+			block["accessory"] = map[string]string{"type": "image", "image_url": image, "alt_text": trackAlt(p.State)}
+		}
+		v = map[string]interface{}{
+			"text":   text,
+			"blocks": []interface{}{block},
+		}
+
+	case FormatMattermost:
+		msg := map[string]interface{}{"text": text}
+		if image != "" {
+			msg["attachments"] = []map[string]string{{"fallback": text, "thumb_url": image}}
+		}
+		v = msg
+
+	case FormatTelegram:
+		url = strings.TrimSuffix(url, "/")
+		if image != "" {
+			url += "/sendPhoto"
+			v = map[string]string{"chat_id": h.ChatID, "photo": image, "caption": text}
+		} else {
+			url += "/sendMessage"
+			v = map[string]string{"chat_id": h.ChatID, "text": text}
+		}
+
+	default:
+		return "", nil, fmt.Errorf("unknown webhook format %q", h.Format)
+	}
+
+	body, err := json.Marshal(v)
+	return url, body, err
+}
+
+func trackAlt(st state.State) string {
+	if st.Artist == "" {
+		return st.Song
+	}
+	return st.Artist + " — " + st.Song
+}
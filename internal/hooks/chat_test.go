@@ -0,0 +1,148 @@
+package hooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/isalikov/radio-record-cli/internal/config"
+	"github.com/isalikov/radio-record-cli/internal/state"
+)
+
+type chatRequest struct {
+	path string
+	body map[string]interface{}
+}
+
+// chatServer is a local stand-in for chat incoming webhooks
+func chatServer(t *testing.T) (*httptest.Server, <-chan chatRequest) {
+	requests := make(chan chatRequest, 8)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Invalid JSON: %v", err)
+		}
+		requests <- chatRequest{path: r.URL.Path, body: body}
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func TestWebhookRequest(t *testing.T) {
+	p := Payload{
+		Event: state.EventTrackChange,
+		State: state.State{Station: "Record", Artist: "Artist", Song: "Song", Image: "https://example.com/cover.jpg"},
+	}
+
+	tests := []struct {
+		hook config.Hook
+		url  string
+		key  string
+		text string
+	}{
+		{config.Hook{URL: "https://hooks.slack.com/x", Format: FormatSlack}, "https://hooks.slack.com/x", "text", "🎵 Artist — Song · Record"},
+		{config.Hook{URL: "https://mm.example.com/hooks/x", Format: FormatMattermost, Template: "{artist}: {song}"}, "https://mm.example.com/hooks/x", "text", "Artist: Song"},
+		{config.Hook{URL: "https://api.telegram.org/bot123/", Format: FormatTelegram, ChatID: "-100"}, "https://api.telegram.org/bot123/sendPhoto", "caption", "🎵 Artist — Song · Record"},
+	}
+
+	for _, tt := range tests {
+		url, body, err := webhookRequest(tt.hook, p)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.hook.Format, err)
+		}
+		if url != tt.url {
+			t.Errorf("%s: expected URL %s, got %s", tt.hook.Format, tt.url, url)
+		}
+
+		var msg map[string]interface{}
+		json.Unmarshal(body, &msg)
+		if msg[tt.key] != tt.text {
+			t.Errorf("%s: expected %s %q, got %v", tt.hook.Format, tt.key, tt.text, msg[tt.key])
+		}
+	}
+
+	if _, _, err := webhookRequest(config.Hook{Format: "irc"}, p); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestChatDedupe(t *testing.T) {
+	server, requests := chatServer(t)
+
+	store := state.NewStore("")
+	hook := config.Hook{URL: server.URL, Format: FormatMattermost, Station: "office"}
+	stop := New([]config.Hook{hook}).Run(store)
+	defer stop()
+
+	play := func(prefix string, trackID int) {
+		store.Update(func(s *state.State) {
+			s.Playing = true
+			s.Prefix = prefix
+			s.TrackID = trackID
+			s.Artist = "Artist"
+			s.Song = "Song"
+		})
+	}
+	play("office", 1)
+	play("deep", 2)   // other station
+	play("office", 1) // same track after switching back
+	play("office", 3)
+
+	for _, expected := range []string{"first", "second"} {
+		select {
+		case r := <-requests:
+			if r.body["text"] == nil {
+				t.Errorf("Expected text in the %s message, got %v", expected, r.body)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected the %s message", expected)
+		}
+	}
+
+	select {
+	case r := <-requests:
+		t.Errorf("Unexpected duplicate message %v", r.body)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestChatDedupePerStation(t *testing.T) {
+	server, requests := chatServer(t)
+
+	store := state.NewStore("")
+	hook := config.Hook{URL: server.URL, Format: FormatSlack}
+	stop := New([]config.Hook{hook}).Run(store)
+	defer stop()
+
+	for _, st := range []struct {
+		prefix  string
+		trackID int
+	}{
+		{"office", 1},
+		{"deep", 2},
+		{"office", 1}, // same track after switching back
+		{"office", 3},
+	} {
+		store.Update(func(s *state.State) {
+			s.Playing = true
+			s.Prefix = st.prefix
+			s.TrackID = st.trackID
+			s.Song = "Song"
+		})
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-requests:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected message %d of 3", i+1)
+		}
+	}
+	select {
+	case r := <-requests:
+		t.Errorf("Unexpected duplicate message %v", r.body)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
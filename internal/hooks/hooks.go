@@ -0,0 +1,253 @@
+// Package hooks runs user commands and posts webhooks on player events.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/isalikov/radio-record-cli/internal/config"
+	"github.com/isalikov/radio-record-cli/internal/state"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+	defaultRetries = 2
+	retryDelay     = time.Second
+	// queueSize is the number of events waiting per hook. A hook that
+	// falls behind drops new events instead of blocking others.
+	queueSize = 16
+)
+
+// Payload is the JSON sent to commands on stdin and to webhooks
+type Payload struct {
+	Event string      `json:"event"`
+	Time  time.Time   `json:"time"`
+	State state.State `json:"state"`
+}
+
+// Runner delivers state events to configured hooks
+type Runner struct {
+	hooks  []config.Hook
+	client *http.Client
+
+	// OnError is called when a hook fails, may be nil
+	OnError func(h config.Hook, err error)
+}
+
+func New(hooks []config.Hook) *Runner {
+	return &Runner{
+		hooks:  hooks,
+		client: &http.Client{},
+	}
+}
+
+// Run delivers events from the store until the returned stop function is
+// called. Each hook has its own queue, so events reach it in order and a
+// slow hook does not delay the others.
+func (r *Runner) Run(store *state.Store) (stop func()) {
+	if len(r.hooks) == 0 {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	queues := make([]chan Payload, len(r.hooks))
+	for i, h := range r.hooks {
+		queues[i] = make(chan Payload, queueSize)
+		go r.worker(ctx, h, queues[i])
+	}
+
+	events, unsubscribe := store.Subscribe()
+	go func() {
+		for e := range events {
+			p := Payload{Event: e.Type, Time: e.State.UpdatedAt, State: e.State}
+			for i, h := range r.hooks {
+				if !matches(h, p) {
+					continue
+				}
+				select {
+				case queues[i] <- p:
+				default:
+				}
+			}
+		}
+		for _, q := range queues {
+			close(q)
+		}
+	}()
+
+	return func() {
+		unsubscribe()
+		cancel()
+	}
+}
+
+func (r *Runner) worker(ctx context.Context, h config.Hook, queue <-chan Payload) {
+	// Chat hooks post every track once, even when the station is switched
+	// away and back or the same track is reported again: the last track is
+	// remembered per station
+	lastTrack := make(map[string]int)
+	for p := range queue {
+		if isChat(h) && p.Event == state.EventTrackChange {
+			if p.State.TrackID == lastTrack[p.State.Prefix] {
+				continue
+			}
+			lastTrack[p.State.Prefix] = p.State.TrackID
+		}
+		// A failed error hook is not reported: the report would be another
+		// error event for the same hook
+		err := r.Deliver(ctx, h, p)
+		if err != nil && r.OnError != nil && p.Event != state.EventError {
+			r.OnError(h, err)
+		}
+	}
+}
+
+// Deliver runs the hook command and posts the webhook for a single event
+func (r *Runner) Deliver(ctx context.Context, h config.Hook, p Payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	var cmdErr, urlErr error
+	if h.Command != "" {
+		cmdErr = r.runCommand(ctx, h, p, body)
+	}
+	if h.URL != "" {
+		urlErr = r.post(ctx, h, p)
+	}
+	if cmdErr != nil {
+		return cmdErr
+	}
+	return urlErr
+}
+
+func (r *Runner) runCommand(ctx context.Context, h config.Hook, p Payload, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout(h))
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", h.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", h.Command)
+	}
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(), Env(p)...)
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("hook %q: %w", h.Command, err)
+	}
+	return nil
+}
+
+// post sends the event to the webhook, retrying on network errors,
+// 429 and 5xx responses
+func (r *Runner) post(ctx context.Context, h config.Hook, p Payload) error {
+	url, body, err := webhookRequest(h, p)
+	if err != nil {
+		return err
+	}
+
+	retries := h.Retries
+	switch {
+	case retries == 0:
+		retries = defaultRetries
+	case retries < 0:
+		retries = 0
+	}
+
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(retryDelay * time.Duration(attempt)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		var retry bool
+		retry, err = r.postOnce(ctx, h, url, body)
+		if err == nil || !retry {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("webhook %s: %w", h.URL, err)
+	}
+	return nil
+}
+
+func (r *Runner) postOnce(ctx context.Context, h config.Hook, url string, body []byte) (retry bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout(h))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "radio-record-cli")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, fmt.Errorf("%s", resp.Status)
+	}
+	return false, nil
+}
+
+// Env returns event data as RADIO_* environment variables
+func Env(p Payload) []string {
+	s := p.State
+	return []string{
+		"RADIO_EVENT=" + p.Event,
+		"RADIO_PLAYING=" + strconv.FormatBool(s.Playing),
+		"RADIO_STATION_ID=" + strconv.Itoa(s.StationID),
+		"RADIO_STATION=" + s.Station,
+		"RADIO_PREFIX=" + s.Prefix,
+		"RADIO_TRACK_ID=" + strconv.Itoa(s.TrackID),
+		"RADIO_ARTIST=" + s.Artist,
+		"RADIO_SONG=" + s.Song,
+		"RADIO_IMAGE=" + s.Image,
+		"RADIO_VOLUME=" + strconv.Itoa(s.Volume),
+		"RADIO_ERROR=" + s.Error,
+	}
+}
+
+// matches reports whether the hook wants the event. Chat hooks default
+// to track changes only.
+func matches(h config.Hook, p Payload) bool {
+	if h.Station != "" && h.Station != p.State.Prefix {
+		return false
+	}
+	if len(h.Events) == 0 {
+		return !isChat(h) || p.Event == state.EventTrackChange
+	}
+	for _, e := range h.Events {
+		if e == p.Event {
+			return true
+		}
+	}
+	return false
+}
+
+func timeout(h config.Hook) time.Duration {
+	if h.Timeout > 0 {
+		return time.Duration(h.Timeout) * time.Second
+	}
+	return defaultTimeout
+}
@@ -0,0 +1,144 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/isalikov/radio-record-cli/internal/config"
+	"github.com/isalikov/radio-record-cli/internal/state"
+)
+
+func testPayload() Payload {
+	return Payload{
+		Event: state.EventTrackChange,
+		State: state.State{Playing: true, StationID: 1, Station: "Record", Artist: "Artist", Song: "Song", Volume: 70},
+	}
+}
+
+func TestCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	out := filepath.Join(t.TempDir(), "out")
+	h := config.Hook{Command: `printf '%s|%s|' "$RADIO_EVENT" "$RADIO_ARTIST" > ` + out + ` && cat >> ` + out}
+
+	if err := New(nil).Deliver(context.Background(), h, testPayload()); err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, body, _ := strings.Cut(string(data), "|Artist|")
+	if env != "track_change" {
+		t.Errorf("Expected RADIO_EVENT=track_change, got %q", env)
+	}
+
+	var p Payload
+	if err := json.Unmarshal([]byte(body), &p); err != nil {
+		t.Fatalf("Expected JSON on stdin, got %q: %v", body, err)
+	}
+	if p.State.Song != "Song" {
+		t.Errorf("Expected song in payload, got %+v", p.State)
+	}
+}
+
+func TestCommandTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	h := config.Hook{Command: "sleep 5", Timeout: 1}
+	start := time.Now()
+	if err := New(nil).Deliver(context.Background(), h, testPayload()); err == nil {
+		t.Error("Expected timeout error")
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Error("Command was not killed on timeout")
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"event":"track_change"`) {
+			t.Errorf("Unexpected body %s", body)
+		}
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	h := config.Hook{URL: server.URL}
+	if err := New(nil).Deliver(context.Background(), h, testPayload()); err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestWebhookNoRetryOnClientError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	h := config.Hook{URL: server.URL}
+	if err := New(nil).Deliver(context.Background(), h, testPayload()); err == nil {
+		t.Error("Expected error for 404")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 attempt, got %d", calls.Load())
+	}
+}
+
+func TestRunFiltersEvents(t *testing.T) {
+	events := make(chan string, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p Payload
+		json.NewDecoder(r.Body).Decode(&p)
+		events <- p.Event
+	}))
+	defer server.Close()
+
+	store := state.NewStore("")
+	runner := New([]config.Hook{{URL: server.URL, Events: []string{state.EventStop}}})
+	stop := runner.Run(store)
+	defer stop()
+
+	store.Update(func(s *state.State) { s.Playing = true; s.StationID = 1 })
+	store.Update(func(s *state.State) { s.Volume = 50 })
+	store.Update(func(s *state.State) { s.Playing = false })
+
+	select {
+	case e := <-events:
+		if e != state.EventStop {
+			t.Errorf("Expected stop event, got %s", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Webhook was not called")
+	}
+
+	select {
+	case e := <-events:
+		t.Errorf("Unexpected event %s", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,267 @@
+// Package metrics exposes listening and API health metrics in the
+// OpenMetrics text format.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isalikov/radio-record-cli/internal/state"
+)
+
+// ContentType of the OpenMetrics text exposition format
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// latencyBuckets are upper bounds of the API latency histogram in seconds
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PlayerStats reports mpv process statistics
+type PlayerStats interface {
+	Starts() int
+	Exits() int
+}
+
+type requestStats struct {
+	count   int
+	errors  int
+	sum     float64
+	buckets []int // cumulative counts per latencyBuckets
+}
+
+type stationKey struct {
+	id     int
+	prefix string
+}
+
+// Metrics collects API requests and listening statistics
+type Metrics struct {
+	store  *state.Store
+	player PlayerStats
+
+	mu           sync.Mutex
+	requests     map[string]*requestStats
+	listened     map[stationKey]float64 // finished listening seconds
+	trackChanges map[stationKey]int
+	playingSince time.Time
+	playingOn    stationKey
+}
+
+// New creates metrics for the given store and player. player may be nil.
+func New(store *state.Store, player PlayerStats) *Metrics {
+	return &Metrics{
+		store:        store,
+		player:       player,
+		requests:     make(map[string]*requestStats),
+		listened:     make(map[stationKey]float64),
+		trackChanges: make(map[stationKey]int),
+	}
+}
+
+// ObserveRequest records an API request. It matches api.RequestObserver.
+func (m *Metrics) ObserveRequest(endpoint string, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r := m.requests[endpoint]
+	if r == nil {
+		r = &requestStats{buckets: make([]int, len(latencyBuckets))}
+		m.requests[endpoint] = r
+	}
+
+	seconds := duration.Seconds()
+	r.count++
+	r.sum += seconds
+	if err != nil {
+		r.errors++
+	}
+	for i, le := range latencyBuckets {
+		if seconds <= le {
+			r.buckets[i]++
+		}
+	}
+}
+
+// Run consumes state events until the returned stop function is called
+func (m *Metrics) Run() (stop func()) {
+	events, unsubscribe := m.store.Subscribe()
+	go func() {
+		for e := range events {
+			m.handle(e)
+		}
+	}()
+	return unsubscribe
+}
+
+func (m *Metrics) handle(e state.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch e.Type {
+	case state.EventPlay, state.EventStop:
+		if e.Prev.Playing && !m.playingSince.IsZero() {
+			m.listened[m.playingOn] += e.State.UpdatedAt.Sub(m.playingSince).Seconds()
+		}
+		m.playingSince = time.Time{}
+		if e.State.Playing {
+			m.playingOn = stationKey{e.State.StationID, e.State.Prefix}
+			m.playingSince = e.State.UpdatedAt
+		}
+	case state.EventTrackChange:
+		m.trackChanges[stationKey{e.State.StationID, e.State.Prefix}]++
+	}
+}
+
+// ServeHTTP writes all metrics
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	m.Write(w, time.Now())
+}
+
+// ListenAndServe serves only /metrics on addr until ctx is cancelled. It
+// lets a scraper read metrics without the remote control API and its token.
+func (m *Metrics) ListenAndServe(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(_ net.Listener) context.Context { return ctx },
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// Write writes all metrics in the OpenMetrics text format
+func (m *Metrics) Write(w io.Writer, now time.Time) {
+	st := m.store.Get()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// API requests
+	endpoints := make([]string, 0, len(m.requests))
+	for e := range m.requests {
+		endpoints = append(endpoints, e)
+	}
+	sort.Strings(endpoints)
+
+	fmt.Fprintln(w, "# TYPE radio_record_api_requests counter")
+	fmt.Fprintln(w, "# HELP radio_record_api_requests Radio Record API requests.")
+	for _, e := range endpoints {
+		fmt.Fprintf(w, "radio_record_api_requests_total{endpoint=%s} %d\n", quote(e), m.requests[e].count)
+	}
+
+	fmt.Fprintln(w, "# TYPE radio_record_api_errors counter")
+	fmt.Fprintln(w, "# HELP radio_record_api_errors Failed Radio Record API requests.")
+	for _, e := range endpoints {
+		fmt.Fprintf(w, "radio_record_api_errors_total{endpoint=%s} %d\n", quote(e), m.requests[e].errors)
+	}
+
+	fmt.Fprintln(w, "# TYPE radio_record_api_request_duration_seconds histogram")
+	fmt.Fprintln(w, "# UNIT radio_record_api_request_duration_seconds seconds")
+	fmt.Fprintln(w, "# HELP radio_record_api_request_duration_seconds Radio Record API request latency.")
+	for _, e := range endpoints {
+		r := m.requests[e]
+		for i, le := range latencyBuckets {
+			fmt.Fprintf(w, "radio_record_api_request_duration_seconds_bucket{endpoint=%s,le=\"%s\"} %d\n", quote(e), formatFloat(le), r.buckets[i])
+		}
+		fmt.Fprintf(w, "radio_record_api_request_duration_seconds_bucket{endpoint=%s,le=\"+Inf\"} %d\n", quote(e), r.count)
+		fmt.Fprintf(w, "radio_record_api_request_duration_seconds_sum{endpoint=%s} %s\n", quote(e), formatFloat(r.sum))
+		fmt.Fprintf(w, "radio_record_api_request_duration_seconds_count{endpoint=%s} %d\n", quote(e), r.count)
+	}
+
+	// Player
+	if m.player != nil {
+		fmt.Fprintln(w, "# TYPE radio_record_mpv_starts counter")
+		fmt.Fprintln(w, "# HELP radio_record_mpv_starts mpv processes started, one per station switch or quality change.")
+		fmt.Fprintf(w, "radio_record_mpv_starts_total %d\n", m.player.Starts())
+
+		fmt.Fprintln(w, "# TYPE radio_record_mpv_exits counter")
+		fmt.Fprintln(w, "# HELP radio_record_mpv_exits Unexpected mpv exits: crashes and dropped streams.")
+		fmt.Fprintf(w, "radio_record_mpv_exits_total %d\n", m.player.Exits())
+	}
+
+	fmt.Fprintln(w, "# TYPE radio_record_playing gauge")
+	fmt.Fprintln(w, "# HELP radio_record_playing Whether a station is playing.")
+	fmt.Fprintf(w, "radio_record_playing %d\n", boolInt(st.Playing))
+
+	fmt.Fprintln(w, "# TYPE radio_record_station info")
+	fmt.Fprintln(w, "# HELP radio_record_station Current station.")
+	if st.Playing {
+		fmt.Fprintf(w, "radio_record_station_info{station_id=\"%d\",station=%s,title=%s} 1\n", st.StationID, quote(st.Prefix), quote(st.Station))
+	}
+
+	fmt.Fprintln(w, "# TYPE radio_record_volume gauge")
+	fmt.Fprintln(w, "# HELP radio_record_volume Player volume, 0-100.")
+	fmt.Fprintf(w, "radio_record_volume %d\n", st.Volume)
+
+	// Listening
+	listened := make(map[stationKey]float64, len(m.listened)+1)
+	for k, v := range m.listened {
+		listened[k] = v
+	}
+	if !m.playingSince.IsZero() {
+		listened[m.playingOn] += now.Sub(m.playingSince).Seconds()
+	}
+
+	fmt.Fprintln(w, "# TYPE radio_record_listening_seconds counter")
+	fmt.Fprintln(w, "# UNIT radio_record_listening_seconds seconds")
+	fmt.Fprintln(w, "# HELP radio_record_listening_seconds Time spent listening per station.")
+	for _, k := range sortedKeys(listened) {
+		fmt.Fprintf(w, "radio_record_listening_seconds_total{station_id=\"%d\",station=%s} %s\n", k.id, quote(k.prefix), formatFloat(listened[k]))
+	}
+
+	fmt.Fprintln(w, "# TYPE radio_record_track_changes counter")
+	fmt.Fprintln(w, "# HELP radio_record_track_changes Track changes heard per station.")
+	for _, k := range sortedKeys(m.trackChanges) {
+		fmt.Fprintf(w, "radio_record_track_changes_total{station_id=\"%d\",station=%s} %d\n", k.id, quote(k.prefix), m.trackChanges[k])
+	}
+
+	fmt.Fprintln(w, "# EOF")
+}
+
+func sortedKeys[V any](m map[stationKey]V) []stationKey {
+	keys := make([]stationKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].id < keys[j].id })
+	return keys
+}
+
+// quote formats a label value: OpenMetrics escapes only backslash,
+// double quote and newline
+func quote(s string) string {
+	return `"` + labelEscaper.Replace(s) + `"`
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(f float64) string {
+	return fmt.Sprintf("%g", f)
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isalikov/radio-record-cli/internal/state"
+)
+
+type fakePlayer int
+
+func (f fakePlayer) Starts() int { return int(f) }
+func (f fakePlayer) Exits() int  { return int(f) - 2 }
+
+func TestWrite(t *testing.T) {
+	store := state.NewStore("")
+	m := New(store, fakePlayer(3))
+
+	m.ObserveRequest("stations", 80*time.Millisecond, nil)
+	m.ObserveRequest("history", 300*time.Millisecond, nil)
+	m.ObserveRequest("history", 20*time.Second, errors.New("timeout"))
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	play := state.State{Playing: true, StationID: 1, Prefix: "rr_main", Station: `Record "Main"`, Volume: 70, UpdatedAt: start}
+	m.handle(state.Event{Type: state.EventPlay, State: play})
+	m.handle(state.Event{Type: state.EventTrackChange, State: play})
+
+	stop := play
+	stop.Playing = false
+	stop.UpdatedAt = start.Add(90 * time.Second)
+	m.handle(state.Event{Type: state.EventStop, State: stop, Prev: play})
+
+	other := state.State{Playing: true, StationID: 2, Prefix: "deep", UpdatedAt: start.Add(100 * time.Second)}
+	m.handle(state.Event{Type: state.EventPlay, State: other, Prev: stop})
+	store.Update(func(s *state.State) { *s = other; s.Station = `Record "Deep"`; s.Volume = 70 })
+
+	var buf bytes.Buffer
+	m.Write(&buf, start.Add(130*time.Second))
+	out := buf.String()
+
+	expected := []string{
+		`radio_record_api_requests_total{endpoint="history"} 2`,
+		`radio_record_api_errors_total{endpoint="history"} 1`,
+		`radio_record_api_request_duration_seconds_bucket{endpoint="history",le="0.5"} 1`,
+		`radio_record_api_request_duration_seconds_bucket{endpoint="history",le="+Inf"} 2`,
+		`radio_record_api_request_duration_seconds_bucket{endpoint="stations",le="0.1"} 1`,
+		`radio_record_mpv_starts_total 3`,
+		`radio_record_mpv_exits_total 1`,
+		`radio_record_playing 1`,
+		`radio_record_station_info{station_id="2",station="deep",title="Record \"Deep\""} 1`,
+		`radio_record_volume 70`,
+		`radio_record_listening_seconds_total{station_id="1",station="rr_main"} 90`,
+		`radio_record_listening_seconds_total{station_id="2",station="deep"} 30`,
+		`radio_record_track_changes_total{station_id="1",station="rr_main"} 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("Expected line %q in output:\n%s", line, out)
+		}
+	}
+
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Error("Expected output to end with # EOF")
+	}
+}
@@ -0,0 +1,17 @@
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// AppleScript shows notifications on macOS via osascript.
+// Notification Center does not allow custom images, so Icon is ignored.
+type AppleScript struct{}
+
+func (AppleScript) Notify(n Notification) error {
+	script := fmt.Sprintf("display notification %s with title %s",
+		strconv.Quote(n.Body), strconv.Quote(n.Title))
+	return exec.Command("osascript", "-e", script).Run()
+}
@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusDest      = "org.freedesktop.Notifications"
+	dbusPath      = "/org/freedesktop/Notifications"
+	dbusNotify    = dbusDest + ".Notify"
+	appName       = "Radio Record CLI"
+	expireDefault = int32(-1)
+)
+
+// DBus sends freedesktop notifications over the session bus. Each new
+// notification replaces the previous one instead of stacking up.
+type DBus struct {
+	conn *dbus.Conn
+
+	mu     sync.Mutex
+	lastID uint32
+}
+
+// NewDBus connects to the session bus at address, or to the default
+// session bus if address is empty.
+func NewDBus(address string) (*DBus, error) {
+	var conn *dbus.Conn
+	var err error
+	if address == "" {
+		conn, err = dbus.ConnectSessionBus()
+	} else {
+		conn, err = dbus.Connect(address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &DBus{conn: conn}, nil
+}
+
+func (d *DBus) Notify(n Notification) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	hints := map[string]dbus.Variant{
+		"category": dbus.MakeVariant("x-radio-record.track"),
+	}
+	if n.Icon != "" {
+		hints["image-path"] = dbus.MakeVariant(n.Icon)
+	}
+
+	obj := d.conn.Object(dbusDest, dbusPath)
+	call := obj.Call(dbusNotify, 0,
+		appName, d.lastID, n.Icon, n.Title, n.Body, []string{}, hints, expireDefault)
+	if call.Err != nil {
+		return call.Err
+	}
+
+	return call.Store(&d.lastID)
+}
+
+// Close closes the bus connection
+func (d *DBus) Close() error {
+	return d.conn.Close()
+}
@@ -0,0 +1,66 @@
+// Package notify shows desktop notifications: freedesktop notifications
+// over D-Bus on Linux and osascript on macOS.
+package notify
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ErrUnsupported is returned when no notification backend is available
+var ErrUnsupported = errors.New("desktop notifications are not supported")
+
+// Notification is a single desktop notification
+type Notification struct {
+	Title string
+	Body  string
+	Icon  string // Path to a local image, may be empty
+}
+
+// Notifier shows desktop notifications
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+// New returns a notifier for the current platform
+func New() (Notifier, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return &AppleScript{}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return NewDBus("")
+	}
+	return nil, ErrUnsupported
+}
+
+// RateLimited drops notifications that come sooner than Interval after
+// the previous shown one
+type RateLimited struct {
+	Notifier Notifier
+	Interval time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+	now  func() time.Time
+}
+
+func NewRateLimited(n Notifier, interval time.Duration) *RateLimited {
+	return &RateLimited{Notifier: n, Interval: interval, now: time.Now}
+}
+
+// Notify shows the notification unless rate limited. Dropped notifications
+// are not an error.
+func (r *RateLimited) Notify(n Notification) error {
+	r.mu.Lock()
+	now := r.now()
+	if !r.last.IsZero() && now.Sub(r.last) < r.Interval {
+		r.mu.Unlock()
+		return nil
+	}
+	r.last = now
+	r.mu.Unlock()
+
+	return r.Notifier.Notify(n)
+}
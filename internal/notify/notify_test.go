@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"bufio"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// fakeServer implements org.freedesktop.Notifications.Notify
+type fakeServer struct {
+	mu       sync.Mutex
+	received []Notification
+	replaces []uint32
+}
+
+func (f *fakeServer) Notify(app string, replaces uint32, icon, summary, body string,
+	actions []string, hints map[string]dbus.Variant, timeout int32) (uint32, *dbus.Error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.received = append(f.received, Notification{Title: summary, Body: body, Icon: icon})
+	f.replaces = append(f.replaces, replaces)
+	return uint32(len(f.received)), nil
+}
+
+// startPrivateBus runs a private session bus and returns its address
+func startPrivateBus(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("dbus-daemon"); err != nil {
+		t.Skip("dbus-daemon not found")
+	}
+
+	cmd := exec.Command("dbus-daemon", "--session", "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("dbus-daemon failed to start: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Reading bus address failed: %v", err)
+	}
+	return strings.TrimSpace(address)
+}
+
+func TestDBusNotify(t *testing.T) {
+	address := startPrivateBus(t)
+
+	server, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer server.Close()
+
+	fake := &fakeServer{}
+	if err := server.Export(fake, dbusPath, dbusDest); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	reply, err := server.RequestName(dbusDest, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("RequestName failed: %v (reply %d)", err, reply)
+	}
+
+	client, err := NewDBus(address)
+	if err != nil {
+		t.Fatalf("NewDBus failed: %v", err)
+	}
+	defer client.Close()
+
+	first := Notification{Title: "Record", Body: "Artist — Song", Icon: "/tmp/cover.jpg"}
+	if err := client.Notify(first); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	if err := client.Notify(Notification{Title: "Record", Body: "Next — Track"}); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	if len(fake.received) != 2 || fake.received[0] != first {
+		t.Fatalf("Unexpected notifications: %+v", fake.received)
+	}
+	// Second notification replaces the first one
+	if fake.replaces[0] != 0 || fake.replaces[1] != 1 {
+		t.Errorf("Expected replaces IDs [0 1], got %v", fake.replaces)
+	}
+}
+
+type recorder struct {
+	count int
+}
+
+func (r *recorder) Notify(Notification) error {
+	r.count++
+	return nil
+}
+
+func TestRateLimited(t *testing.T) {
+	rec := &recorder{}
+	limited := NewRateLimited(rec, 10*time.Second)
+
+	now := time.Now()
+	limited.now = func() time.Time { return now }
+
+	limited.Notify(Notification{})
+	limited.Notify(Notification{}) // dropped
+
+	now = now.Add(11 * time.Second)
+	limited.Notify(Notification{})
+
+	if rec.count != 2 {
+		t.Errorf("Expected 2 notifications, got %d", rec.count)
+	}
+}
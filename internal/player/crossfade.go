@@ -0,0 +1,128 @@
+package player
+
+import (
+	"math"
+	"net"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// fadeStep is how often volumes are updated during a crossfade
+const fadeStep = 50 * time.Millisecond
+
+// startTimeout limits the wait for the new stream to start playing. The
+// old station keeps playing at full level meanwhile, so there is no
+// silence while the new stream connects and buffers. A stream that does
+// not start in time is faded in anyway.
+const startTimeout = 10 * time.Second
+
+// instance is an mpv process with its IPC socket. A nil *instance is valid
+// and does nothing.
+type instance struct {
+	cmd    *exec.Cmd
+	done   <-chan struct{} // Closed by Player.wait when the process exits
+	socket string
+	level  float64 // Fraction of volume it was playing at
+}
+
+// stop kills the process and removes its socket
+func (i *instance) stop() {
+	if i == nil {
+		return
+	}
+	if i.cmd != nil && i.cmd.Process != nil {
+		kill(i.cmd)
+		<-i.done
+	}
+	os.Remove(i.socket)
+}
+
+// SetCrossfade sets how long the previous station fades out while the next
+// one fades in. The first station ramps in over the same time. Zero
+// switches stations at once, as before.
+func (p *Player) SetCrossfade(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if d < 0 {
+		d = 0
+	}
+	p.fade = d
+}
+
+// Crossfade returns the crossfade duration
+func (p *Player) Crossfade() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.fade
+}
+
+// scaleVolume returns the mpv volume for a fraction of the player volume
+func scaleVolume(volume int, level float64) int {
+	return int(math.Round(float64(volume) * level))
+}
+
+// crossfade ramps the mpv on socket up to the player volume and the old
+// one down, then stops the old one. It returns early if Play or Stop is
+// called again.
+func (p *Player) crossfade(seq int, socket string, old *instance) {
+	deadline := time.Now().Add(startTimeout)
+	for !started(socket) && time.Now().Before(deadline) {
+		if !p.fadeActive(seq) {
+			return
+		}
+		time.Sleep(fadeStep)
+	}
+
+	start := time.Now()
+	for {
+		p.mu.Lock()
+		if p.fadeSeq != seq {
+			p.mu.Unlock()
+			return
+		}
+
+		k := 1.0
+		if p.fade > 0 {
+			k = math.Min(1, float64(time.Since(start))/float64(p.fade))
+		}
+		p.level = k
+		send(socket, []interface{}{"set_property", "volume", scaleVolume(p.volume, k)})
+		if old != nil {
+			send(old.socket, []interface{}{"set_property", "volume", scaleVolume(p.volume, old.level*(1-k))})
+		}
+
+		if k >= 1 {
+			old.stop()
+			p.fading = nil
+			p.mu.Unlock()
+			return
+		}
+		p.mu.Unlock()
+		time.Sleep(fadeStep)
+	}
+}
+
+func (p *Player) fadeActive(seq int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.fadeSeq == seq
+}
+
+// started reports whether the mpv on socket plays audio. mpv opens its
+// socket right after start, long before the stream is connected; until
+// then and while buffering core-idle is true.
+func started(socket string) bool {
+	idle := true
+	return query(socket, []interface{}{"get_property", "core-idle"}, &idle) == nil && !idle
+}
+
+// socketReady reports whether mpv accepts connections on socket
+func socketReady(socket string) bool {
+	conn, err := net.DialTimeout("unix", socket, 100*time.Millisecond)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
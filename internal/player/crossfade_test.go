@@ -0,0 +1,169 @@
+package player
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// volumeRecorder collects volumes set over a fake mpv socket. It reports
+// core-idle until the stream "starts" after idleQueries queries.
+type volumeRecorder struct {
+	mu          sync.Mutex
+	volumes     []int
+	idleQueries int
+}
+
+func listenVolumes(t *testing.T, socket string) *volumeRecorder {
+	return listenMPV(t, socket, 0)
+}
+
+func listenMPV(t *testing.T, socket string, idleQueries int) *volumeRecorder {
+	t.Helper()
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	r := &volumeRecorder{idleQueries: idleQueries}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			line, _ := bufio.NewReader(conn).ReadBytes('\n')
+
+			var msg struct{ Command []interface{} }
+			json.Unmarshal(line, &msg)
+			r.mu.Lock()
+			switch {
+			case len(msg.Command) == 2 && msg.Command[1] == "core-idle":
+				idle := r.idleQueries > 0
+				r.idleQueries--
+				reply, _ := json.Marshal(map[string]interface{}{"data": idle, "request_id": 1, "error": "success"})
+				conn.Write(append(reply, '\n'))
+			case len(msg.Command) == 3:
+				r.volumes = append(r.volumes, int(msg.Command[2].(float64)))
+				conn.Write([]byte(`{"request_id":1,"error":"success"}` + "\n"))
+			}
+			r.mu.Unlock()
+			conn.Close()
+		}
+	}()
+	return r
+}
+
+// wait returns the volumes once the last one equals want or after a second
+func (r *volumeRecorder) wait(want int) []int {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if v := r.get(); len(v) > 0 && v[len(v)-1] == want {
+			return v
+		}
+	}
+	return r.get()
+}
+
+func (r *volumeRecorder) get() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int(nil), r.volumes...)
+}
+
+func TestCrossfade(t *testing.T) {
+	dir := t.TempDir()
+	p := New()
+	p.SetCrossfade(200 * time.Millisecond)
+	p.volume = 60
+	p.playing = true
+	p.level = 0
+	p.socketPath = filepath.Join(dir, "new.sock")
+
+	oldVolumes := listenVolumes(t, filepath.Join(dir, "old.sock"))
+	newVolumes := listenVolumes(t, p.socketPath)
+
+	old := &instance{socket: filepath.Join(dir, "old.sock"), level: 1}
+	p.fading = old
+	p.crossfade(p.fadeSeq, p.socketPath, old)
+
+	in, out := newVolumes.wait(60), oldVolumes.wait(0)
+	if len(in) < 2 || in[len(in)-1] != 60 {
+		t.Fatalf("Expected the new station to ramp up to 60, got %v", in)
+	}
+	if out[len(out)-1] != 0 {
+		t.Errorf("Expected the old station to fade out to 0, got %v", out)
+	}
+	for i := 1; i < len(in); i++ {
+		if in[i] < in[i-1] || out[i] > out[i-1] {
+			t.Errorf("Expected monotonic fade, got in %v, out %v", in, out)
+			break
+		}
+	}
+	if p.fading != nil || p.level != 1 {
+		t.Errorf("Expected the fade finished, level %g", p.level)
+	}
+}
+
+func TestCrossfadeCancelled(t *testing.T) {
+	dir := t.TempDir()
+	p := New()
+	p.SetCrossfade(time.Second)
+	p.socketPath = filepath.Join(dir, "new.sock")
+	volumes := listenVolumes(t, p.socketPath)
+
+	done := make(chan struct{})
+	go func() {
+		p.crossfade(p.fadeSeq, p.socketPath, nil)
+		close(done)
+	}()
+
+	time.Sleep(3 * fadeStep)
+	p.Stop()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Stop to cancel the fade")
+	}
+	if v := volumes.get(); len(v) == 0 || v[len(v)-1] == p.Volume() {
+		t.Errorf("Expected the ramp stopped before full volume, got %v", v)
+	}
+}
+
+func TestCrossfadeWaitsForPlayback(t *testing.T) {
+	dir := t.TempDir()
+	p := New()
+	p.SetCrossfade(100 * time.Millisecond)
+	p.volume = 60
+	p.playing = true
+	p.level = 0
+	p.socketPath = filepath.Join(dir, "new.sock")
+
+	// The socket is up at once, but the stream plays only after 6 queries
+	old := &instance{socket: filepath.Join(dir, "old.sock"), level: 1}
+	oldVolumes := listenVolumes(t, old.socket)
+	newVolumes := listenMPV(t, p.socketPath, 6)
+
+	done := make(chan struct{})
+	go func() {
+		p.crossfade(p.fadeSeq, p.socketPath, old)
+		close(done)
+	}()
+
+	time.Sleep(3 * fadeStep)
+	if v := oldVolumes.get(); len(v) > 0 {
+		t.Errorf("Expected the old station untouched while the new one connects, got %v", v)
+	}
+	if v := newVolumes.get(); len(v) > 0 {
+		t.Errorf("Expected no ramp before playback starts, got %v", v)
+	}
+
+	<-done
+	if v := oldVolumes.wait(0); len(v) == 0 || v[len(v)-1] != 0 {
+		t.Errorf("Expected the old station faded out after the start, got %v", v)
+	}
+}
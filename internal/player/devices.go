@@ -0,0 +1,102 @@
+package player
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// AutoAudioDevice lets mpv pick the audio output
+const AutoAudioDevice = "auto"
+
+// ErrUnknownDevice is returned for an audio device mpv does not list
+var ErrUnknownDevice = errors.New("unknown audio device")
+
+// AudioDevice is an mpv audio output such as "pulse/alsa_output.usb-headset"
+type AudioDevice struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// AudioDevices lists the audio outputs mpv can use. While playing the list
+// comes from the running mpv over IPC, otherwise from `mpv --audio-device=help`.
+func (p *Player) AudioDevices() ([]AudioDevice, error) {
+	if p.IsPlaying() {
+		var devices []AudioDevice
+		if err := p.query([]interface{}{"get_property", "audio-device-list"}, &devices); err == nil {
+			return devices, nil
+		}
+	}
+
+	out, err := exec.Command("mpv", "--audio-device=help").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseDeviceList(string(out)), nil
+}
+
+// deviceLine matches "  'alsa/default' (Default ALSA Output)"
+var deviceLine = regexp.MustCompile(`^\s*'([^']+)'\s+\((.*)\)\s*$`)
+
+// parseDeviceList parses the output of `mpv --audio-device=help`
+func parseDeviceList(out string) []AudioDevice {
+	var devices []AudioDevice
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		if match := deviceLine.FindStringSubmatch(scanner.Text()); match != nil {
+			devices = append(devices, AudioDevice{Name: match[1], Description: match[2]})
+		}
+	}
+	return devices
+}
+
+// CheckAudioDevice reports whether mpv lists the device. "" and
+// AutoAudioDevice are always valid.
+func (p *Player) CheckAudioDevice(name string) error {
+	if name == "" || name == AutoAudioDevice {
+		return nil
+	}
+	devices, err := p.AudioDevices()
+	if err != nil {
+		return err
+	}
+	for _, d := range devices {
+		if d.Name == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownDevice, name)
+}
+
+// SetAudioDevice switches the audio output, "" or AutoAudioDevice lets mpv
+// choose. The device is switched live over IPC and kept for the next Play.
+// If the running mpv rejects it, the previous device is kept.
+func (p *Player) SetAudioDevice(name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if name == AutoAudioDevice {
+		name = ""
+	}
+	if p.playing {
+		value := name
+		if value == "" {
+			value = AutoAudioDevice
+		}
+		if err := p.setProperty("audio-device", value); err != nil {
+			return err
+		}
+	}
+	p.device = name
+	return nil
+}
+
+// AudioDevice returns the selected audio output, "" if mpv chooses
+func (p *Player) AudioDevice() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.device
+}
@@ -0,0 +1,126 @@
+package player
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseDeviceList(t *testing.T) {
+	out := `List of detected audio devices:
+  'auto' (Autoselect device)
+  'pulse/alsa_output.usb-Logitech_Headset-00.analog-stereo' (Logitech USB Headset (pulse))
+  'alsa/default:CARD=PCH' (HDA Intel PCH, ALC3246 Analog)
+`
+	devices := parseDeviceList(out)
+	if len(devices) != 3 {
+		t.Fatalf("Expected 3 devices, got %d", len(devices))
+	}
+	if devices[1].Name != "pulse/alsa_output.usb-Logitech_Headset-00.analog-stereo" ||
+		devices[1].Description != "Logitech USB Headset (pulse)" {
+		t.Errorf("Unexpected device %+v", devices[1])
+	}
+	if devices[2].Description != "HDA Intel PCH, ALC3246 Analog" {
+		t.Errorf("Unexpected description %q", devices[2].Description)
+	}
+}
+
+// mpvOK is the reply of mpv to a successful command
+const mpvOK = `{"request_id":1,"error":"success"}` + "\n"
+
+// fakeMPV answers IPC requests on a socket and records them
+func fakeMPV(t *testing.T, replies ...string) (*Player, <-chan string) {
+	t.Helper()
+
+	p := New()
+	p.socketPath = filepath.Join(t.TempDir(), "mpv.sock")
+	p.playing = true
+
+	ln, err := net.Listen("unix", p.socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	requests := make(chan string, 10)
+	go func() {
+		for _, reply := range replies {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			requests <- strings.TrimSpace(line)
+			conn.Write([]byte(reply))
+			conn.Close()
+		}
+	}()
+	return p, requests
+}
+
+func TestAudioDevicesIPC(t *testing.T) {
+	p, requests := fakeMPV(t,
+		`{"event":"audio-reconfig"}`+"\n"+
+			`{"data":[{"name":"auto","description":"Autoselect device"},{"name":"pulse/usb","description":"USB"}],"request_id":1,"error":"success"}`+"\n",
+		mpvOK,
+	)
+
+	devices, err := p.AudioDevices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 2 || devices[1].Name != "pulse/usb" {
+		t.Errorf("Unexpected devices %+v", devices)
+	}
+	if req := <-requests; !strings.Contains(req, `"audio-device-list"`) {
+		t.Errorf("Expected audio-device-list request, got %s", req)
+	}
+
+	if err := p.SetAudioDevice("pulse/usb"); err != nil {
+		t.Fatal(err)
+	}
+	if req := <-requests; req != `{"command":["set_property","audio-device","pulse/usb"],"request_id":1}` {
+		t.Errorf("Unexpected request %s", req)
+	}
+	if p.AudioDevice() != "pulse/usb" {
+		t.Errorf("Expected the device kept for the next Play")
+	}
+}
+
+func TestSetAudioDeviceAuto(t *testing.T) {
+	p := New()
+	p.SetAudioDevice("pulse/usb")
+	if err := p.SetAudioDevice(AutoAudioDevice); err != nil || p.AudioDevice() != "" {
+		t.Errorf("Expected auto to clear the device, got %q, %v", p.AudioDevice(), err)
+	}
+}
+
+func TestSetAudioDeviceRejected(t *testing.T) {
+	p, _ := fakeMPV(t, `{"request_id":1,"error":"property unavailable"}`+"\n")
+	p.device = "alsa/default"
+
+	if err := p.SetAudioDevice("pulse/gone"); err == nil {
+		t.Fatal("Expected the rejected device reported")
+	}
+	if p.AudioDevice() != "alsa/default" {
+		t.Errorf("Expected the previous device kept, got %q", p.AudioDevice())
+	}
+}
+
+func TestCheckAudioDevice(t *testing.T) {
+	list := `{"data":[{"name":"auto","description":"Autoselect device"},{"name":"pulse/usb","description":"USB"}],"request_id":1,"error":"success"}` + "\n"
+	p, _ := fakeMPV(t, list, list)
+
+	if err := p.CheckAudioDevice(AutoAudioDevice); err != nil {
+		t.Errorf("Expected auto to be valid, got %v", err)
+	}
+	if err := p.CheckAudioDevice("pulse/usb"); err != nil {
+		t.Errorf("Expected a listed device to be valid, got %v", err)
+	}
+	if err := p.CheckAudioDevice("pulse/typo"); !errors.Is(err, ErrUnknownDevice) {
+		t.Errorf("Expected ErrUnknownDevice, got %v", err)
+	}
+}
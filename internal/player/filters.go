@@ -0,0 +1,122 @@
+package player
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// EQBands are the center frequencies of the 10-band equalizer in Hz
+var EQBands = []int{31, 62, 125, 250, 500, 1000, 2000, 4000, 8000, 16000}
+
+// MaxGain limits equalizer gains to ±12 dB
+const MaxGain = 12
+
+// Loudness normalizers: loudnorm follows EBU R128, dynaudnorm reacts
+// faster and is lighter on CPU
+const (
+	NormalizeLoudnorm   = "loudnorm"
+	NormalizeDynaudnorm = "dynaudnorm"
+)
+
+// FilterChain builds an mpv audio filter chain from equalizer gains in dB
+// (one per EQBands entry), a loudness normalizer and a downmix to mono.
+// Boosted bands are preceded by a negative preamp of the largest boost, so
+// a loud stream does not clip. Returns "" when nothing is enabled.
+func FilterChain(gains []float64, normalize string, mono bool) string {
+	var filters []string
+	boost := 0.0
+	for i, gain := range gains {
+		if i >= len(EQBands) || gain == 0 {
+			continue
+		}
+		if gain > MaxGain {
+			gain = MaxGain
+		}
+		if gain < -MaxGain {
+			gain = -MaxGain
+		}
+		boost = math.Max(boost, gain)
+		filters = append(filters, fmt.Sprintf("equalizer=f=%d:t=o:w=1:g=%g", EQBands[i], gain))
+	}
+	if boost > 0 {
+		filters = append([]string{fmt.Sprintf("volume=%gdB", -boost)}, filters...)
+	}
+
+	switch normalize {
+	case NormalizeLoudnorm:
+		filters = append(filters, "loudnorm=I=-16:TP=-1.5:LRA=11")
+	case NormalizeDynaudnorm:
+		filters = append(filters, "dynaudnorm=f=250:g=15")
+	}
+
+	if mono {
+		filters = append(filters, "pan=mono|c0=0.5*c0+0.5*c1")
+	}
+
+	if len(filters) == 0 {
+		return ""
+	}
+	return "lavfi=[" + strings.Join(filters, ",") + "]"
+}
+
+// SetAudioFilter sets the mpv audio filter chain, "" removes all filters.
+// The chain is applied live over IPC and kept for the next Play. If the
+// running mpv rejects it, the previous chain is kept.
+func (p *Player) SetAudioFilter(chain string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	prev := p.filter
+	p.filter = chain
+	if err := p.applyFilters(); err != nil {
+		p.filter = prev
+		return err
+	}
+	return nil
+}
+
+// SetTap sets a filter placed after the audio filter chain that copies the
+// audio for the visualizer, "" removes it. Like SetAudioFilter it is
+// applied live and kept for the next Play.
+func (p *Player) SetTap(filter string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.tap == filter {
+		return nil
+	}
+	prev := p.tap
+	p.tap = filter
+	if err := p.applyFilters(); err != nil {
+		p.tap = prev
+		return err
+	}
+	return nil
+}
+
+// applyFilters sends the filters to the running mpv. Called with p.mu held.
+func (p *Player) applyFilters() error {
+	if !p.playing {
+		return nil
+	}
+	return p.setProperty("af", p.audioFilters())
+}
+
+// audioFilters returns the mpv --af list: the chain, then the tap
+func (p *Player) audioFilters() string {
+	var filters []string
+	for _, f := range []string{p.filter, p.tap} {
+		if f != "" {
+			filters = append(filters, f)
+		}
+	}
+	return strings.Join(filters, ",")
+}
+
+// AudioFilter returns the current audio filter chain
+func (p *Player) AudioFilter() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.filter
+}
@@ -0,0 +1,67 @@
+package player
+
+import "testing"
+
+func TestFilterChain(t *testing.T) {
+	tests := []struct {
+		gains     []float64
+		normalize string
+		mono      bool
+		expected  string
+	}{
+		{nil, "", false, ""},
+		{[]float64{0, 0, 0}, "", false, ""},
+		{[]float64{6, 0, -3.5}, "", false, "lavfi=[volume=-6dB,equalizer=f=31:t=o:w=1:g=6,equalizer=f=125:t=o:w=1:g=-3.5]"},
+		{[]float64{20}, "", false, "lavfi=[volume=-12dB,equalizer=f=31:t=o:w=1:g=12]"},
+		{[]float64{-4, -2}, "", false, "lavfi=[equalizer=f=31:t=o:w=1:g=-4,equalizer=f=62:t=o:w=1:g=-2]"},
+		{nil, NormalizeDynaudnorm, true, "lavfi=[dynaudnorm=f=250:g=15,pan=mono|c0=0.5*c0+0.5*c1]"},
+		{nil, "unknown", false, ""},
+	}
+
+	for _, tt := range tests {
+		if got := FilterChain(tt.gains, tt.normalize, tt.mono); got != tt.expected {
+			t.Errorf("FilterChain(%v, %q, %v) = %q, expected %q", tt.gains, tt.normalize, tt.mono, got, tt.expected)
+		}
+	}
+}
+
+func TestSetAudioFilterStopped(t *testing.T) {
+	p := New()
+	if err := p.SetAudioFilter("lavfi=[dynaudnorm]"); err != nil {
+		t.Fatalf("Expected no IPC while stopped, got %v", err)
+	}
+	if p.AudioFilter() != "lavfi=[dynaudnorm]" {
+		t.Errorf("Expected the filter to be kept for the next start, got %q", p.AudioFilter())
+	}
+}
+
+func TestTapAfterFilter(t *testing.T) {
+	p, requests := fakeMPV(t, mpvOK, mpvOK)
+
+	if err := p.SetTap("lavfi=%5%anull"); err != nil {
+		t.Fatal(err)
+	}
+	if req := <-requests; req != `{"command":["set_property","af","lavfi=%5%anull"],"request_id":1}` {
+		t.Errorf("Unexpected request %s", req)
+	}
+
+	// The tap stays last, so it sees the equalized audio
+	if err := p.SetAudioFilter("lavfi=[dynaudnorm]"); err != nil {
+		t.Fatal(err)
+	}
+	if req := <-requests; req != `{"command":["set_property","af","lavfi=[dynaudnorm],lavfi=%5%anull"],"request_id":1}` {
+		t.Errorf("Unexpected request %s", req)
+	}
+}
+
+func TestSetAudioFilterRejected(t *testing.T) {
+	p, _ := fakeMPV(t, `{"request_id":1,"error":"invalid parameter"}`+"\n")
+	p.filter = "lavfi=[dynaudnorm]"
+
+	if err := p.SetAudioFilter("lavfi=[bogus]"); err == nil {
+		t.Fatal("Expected the rejected filter reported")
+	}
+	if p.AudioFilter() != "lavfi=[dynaudnorm]" {
+		t.Errorf("Expected the previous filter kept, got %q", p.AudioFilter())
+	}
+}
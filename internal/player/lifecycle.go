@@ -0,0 +1,55 @@
+package player
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/isalikov/radio-record-cli/internal/rundir"
+)
+
+// socketName matches the mpv sockets and the visualizer FIFO of any
+// instance: the PID of the owning process and an optional suffix of the
+// crossfade socket
+var socketName = regexp.MustCompile(`^radiorecord-(?:mpv-(\d+)(?:-2)?\.sock|viz-(\d+)\.fifo)$`)
+
+// CleanStale removes sockets and visualizer FIFOs left by instances that
+// were killed or crashed, including ones from older versions in the temp
+// dir. An mpv still answering on such a socket is an orphan and is told to
+// quit. Returns the number of removed files.
+func CleanStale() int {
+	removed := cleanStale(rundir.Dir())
+	if dir := os.TempDir(); dir != rundir.Dir() {
+		removed += cleanStale(dir)
+	}
+	return removed
+}
+
+func cleanStale(dir string) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	for _, e := range entries {
+		match := socketName.FindStringSubmatch(e.Name())
+		if match == nil {
+			continue
+		}
+		pid, err := strconv.Atoi(match[1] + match[2])
+		if err != nil || pid == os.Getpid() || alive(pid) {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+		if socketReady(path) {
+			send(path, []interface{}{"quit"})
+		}
+		if os.Remove(path) == nil {
+			removed++
+		}
+	}
+	return removed
+}
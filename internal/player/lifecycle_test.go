@@ -0,0 +1,181 @@
+package player
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// deadPID is above the Linux PID limit, so no such process exists
+const deadPID = "999999999"
+
+func TestSocketDir(t *testing.T) {
+	runtime := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtime)
+
+	p := New()
+	if dir := filepath.Join(runtime, "radio-record-cli"); filepath.Dir(p.socketPath) != dir {
+		t.Errorf("Expected socket in %s, got %s", dir, p.socketPath)
+	}
+	if p.dirErr != nil {
+		t.Errorf("Expected a usable socket dir, got %v", p.dirErr)
+	}
+}
+
+func TestUnsafeSocketDir(t *testing.T) {
+	runtime := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtime)
+	if err := os.Symlink(t.TempDir(), filepath.Join(runtime, "radio-record-cli")); err != nil {
+		t.Skip(err)
+	}
+
+	// Play fails instead of putting the socket somewhere others can reach
+	p := New()
+	if err := p.Play("http://example.com/stream"); err == nil {
+		p.Stop()
+		t.Fatal("Expected Play to fail with an unsafe socket dir")
+	}
+	if p.IsPlaying() {
+		t.Errorf("Expected nothing playing")
+	}
+}
+
+func TestCleanStale(t *testing.T) {
+	dir := t.TempDir()
+	own := filepath.Join(dir, "radiorecord-mpv-"+strconv.Itoa(os.Getpid())+".sock")
+	live := filepath.Join(dir, "radiorecord-mpv-"+strconv.Itoa(os.Getppid())+".sock")
+	stale := filepath.Join(dir, "radiorecord-mpv-"+deadPID+".sock")
+	other := filepath.Join(dir, "radiorecord-mpv-notes.txt")
+	fifo := filepath.Join(dir, "radiorecord-viz-"+deadPID+".fifo")
+	for _, path := range []string{own, live, stale, other, fifo} {
+		os.WriteFile(path, nil, 0600)
+	}
+
+	// An orphan mpv still listening on its socket gets quit
+	orphan := filepath.Join(dir, "radiorecord-mpv-"+deadPID+"-2.sock")
+	ln, err := net.Listen("unix", orphan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	commands := make(chan string, 2)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			commands <- strings.TrimSpace(line)
+			conn.Close()
+		}
+	}()
+
+	if removed := cleanStale(dir); removed != 3 {
+		t.Errorf("Expected 3 stale files removed, got %d", removed)
+	}
+	for _, path := range []string{own, live, other} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s kept", filepath.Base(path))
+		}
+	}
+	for _, path := range []string{stale, orphan, fifo} {
+		if _, err := os.Stat(path); err == nil {
+			t.Errorf("Expected %s removed", filepath.Base(path))
+		}
+	}
+
+	// The readiness probe connects without a command, then quit is sent
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case cmd := <-commands:
+			if cmd == `{"command":["quit"]}` {
+				return
+			}
+		case <-timeout:
+			t.Fatal("Expected quit sent to the orphan mpv")
+		}
+	}
+}
+
+func TestUnexpectedExit(t *testing.T) {
+	p := New()
+	start := func() (*exec.Cmd, chan struct{}) {
+		// The test binary without tests exits at once, like a crashed mpv
+		cmd := exec.Command(os.Args[0], "-test.run=^$")
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		return cmd, make(chan struct{})
+	}
+
+	// The current mpv exits on its own
+	p.mu.Lock()
+	p.cmd, p.done = start()
+	go p.wait(p.cmd, p.done)
+	p.mu.Unlock()
+	<-p.done
+	deadline := time.Now().Add(time.Second)
+	for p.Exits() != 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if p.Exits() != 1 {
+		t.Fatalf("Expected 1 unexpected exit, got %d", p.Exits())
+	}
+
+	// An mpv that is no longer current was replaced by Play or Stop
+	cmd, done := start()
+	go p.wait(cmd, done)
+	<-done
+	time.Sleep(50 * time.Millisecond)
+	if p.Exits() != 1 {
+		t.Errorf("Expected a replaced mpv not counted, got %d exits", p.Exits())
+	}
+}
+
+func TestPlayStartFails(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("PATH", t.TempDir()) // No mpv to start
+
+	p := New()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	p.mu.Lock()
+	p.cmd, p.done = cmd, make(chan struct{})
+	go p.wait(p.cmd, p.done)
+	p.playing = true
+	p.streamURL = "old"
+	p.mu.Unlock()
+	socket := p.socketPath
+
+	// During a crossfade the old station keeps playing
+	p.SetCrossfade(time.Second)
+	if err := p.Play("new"); err == nil {
+		t.Fatal("Expected Play to fail without mpv")
+	}
+	if !p.IsPlaying() || p.cmd != cmd || p.socketPath != socket || p.streamURL != "old" {
+		t.Errorf("Expected the old station kept, got playing %v, socket %s, url %s", p.IsPlaying(), p.socketPath, p.streamURL)
+	}
+
+	// Without a fade the old station is stopped first
+	p.SetCrossfade(0)
+	if err := p.Play("new"); err == nil {
+		t.Fatal("Expected Play to fail without mpv")
+	}
+	if p.IsPlaying() || p.cmd != nil {
+		t.Errorf("Expected the player stopped, got playing %v", p.IsPlaying())
+	}
+	if p.Starts() != 0 {
+		t.Errorf("Expected no starts counted, got %d", p.Starts())
+	}
+}
@@ -9,74 +9,167 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/isalikov/radio-record-cli/internal/rundir"
 )
 
 type Player struct {
 	cmd        *exec.Cmd
+	done       chan struct{} // Closed when cmd exits
 	streamURL  string
 	playing    bool
 	volume     int
-	socketPath string
+	socketPath string    // Socket of the current mpv
+	sockets    [2]string // Two sockets so the next station can start while the old one fades out
+	starts     int
+	exits      int    // mpv exits that were not asked for
+	filter     string // mpv --af chain, see FilterChain
+	tap        string // Visualizer filter after the chain
+	device     string // mpv --audio-device, "" lets mpv choose
+	fade       time.Duration
+	fading     *instance // Previous station fading out
+	fadeSeq    int       // Incremented to cancel a running fade
+	level      float64   // Fraction of volume the current mpv plays at during a fade
+	dirErr     error     // Socket directory is missing or unsafe
 	mu         sync.Mutex
 }
 
 func New() *Player {
-	socketPath := filepath.Join(os.TempDir(), fmt.Sprintf("radiorecord-mpv-%d.sock", os.Getpid()))
+	// Sockets live in a private directory so other users cannot control mpv.
+	// If it is unsafe, Play reports the error instead of falling back to
+	// the shared temp dir.
+	dir, err := rundir.Ensure()
+	base := filepath.Join(dir, fmt.Sprintf("radiorecord-mpv-%d", os.Getpid()))
 	return &Player{
+		dirErr:     err,
 		volume:     80,
-		socketPath: socketPath,
+		socketPath: base + ".sock",
+		sockets:    [2]string{base + ".sock", base + "-2.sock"},
+		level:      1,
 	}
 }
 
-// Play starts playing the given stream URL
+// Play starts playing the given stream URL. With a crossfade the previous
+// station keeps playing in its own mpv and fades out while the new one
+// fades in.
 func (p *Player) Play(url string) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	// Stop current playback if any
-	if p.cmd != nil && p.cmd.Process != nil {
-		p.cmd.Process.Kill()
-		p.cmd.Wait()
+	if p.dirErr != nil {
+		return p.dirErr
+	}
+
+	p.fadeSeq++
+	p.fading.stop()
+	p.fading = nil
+
+	// A crossfade keeps the current mpv playing on its socket until the
+	// new one is up, otherwise the current one is stopped first
+	crossfade := p.fade > 0 && p.playing && p.cmd != nil
+	socket := p.socketPath
+	if crossfade {
+		socket = p.nextSocket()
+	} else {
+		(&instance{cmd: p.cmd, done: p.done, socket: p.socketPath}).stop()
+		p.cmd = nil
+		p.playing = false
 	}
 
 	// Remove old socket
-	os.Remove(p.socketPath)
+	os.Remove(socket)
 
-	p.streamURL = url
-	p.cmd = exec.Command("mpv",
+	volume, level := p.volume, 1.0
+	if p.fade > 0 {
+		volume, level = 0, 0
+	}
+
+	args := []string{
 		"--no-video",
 		"--quiet",
 		"--no-terminal",
-		fmt.Sprintf("--volume=%d", p.volume),
-		fmt.Sprintf("--input-ipc-server=%s", p.socketPath),
-		url,
-	)
+		fmt.Sprintf("--volume=%d", volume),
+		fmt.Sprintf("--input-ipc-server=%s", socket),
+	}
+	if af := p.audioFilters(); af != "" {
+		args = append(args, "--af="+af)
+	}
+	if p.device != "" {
+		args = append(args, "--audio-device="+p.device)
+	}
+	cmd := exec.Command("mpv", append(args, url)...)
+	ConfigureCommand(cmd)
 
-	if err := p.cmd.Start(); err != nil {
+	// On failure the player stays as it was: still playing the old
+	// station during a crossfade, stopped otherwise
+	if err := cmd.Start(); err != nil {
 		return err
 	}
 
+	var old *instance
+	if crossfade {
+		old = &instance{cmd: p.cmd, done: p.done, socket: p.socketPath, level: p.level}
+	}
+	p.cmd = cmd
+	p.done = make(chan struct{})
+	go p.wait(p.cmd, p.done)
+
+	p.socketPath = socket
+	p.level = level
+	p.streamURL = url
+	p.starts++
 	p.playing = true
+	if p.fade > 0 {
+		p.fading = old
+		go p.crossfade(p.fadeSeq, p.socketPath, old)
+	}
 	return nil
 }
 
+// wait reaps the mpv process. An exit of the current mpv that Play or
+// Stop did not cause is counted as a crash, e.g. a dropped stream.
+func (p *Player) wait(cmd *exec.Cmd, done chan struct{}) {
+	cmd.Wait()
+	close(done)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cmd == cmd {
+		p.exits++
+	}
+}
+
+// nextSocket returns the socket not used by the current mpv
+func (p *Player) nextSocket() string {
+	if p.socketPath == p.sockets[0] {
+		return p.sockets[1]
+	}
+	return p.sockets[0]
+}
+
 // Stop stops the current playback
 func (p *Player) Stop() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	if p.cmd != nil && p.cmd.Process != nil {
-		p.cmd.Process.Kill()
-		p.cmd.Wait()
-		p.cmd = nil
-	}
-	os.Remove(p.socketPath)
+	p.fadeSeq++
+	p.fading.stop()
+	p.fading = nil
+
+	(&instance{cmd: p.cmd, done: p.done, socket: p.socketPath}).stop()
+	p.cmd = nil
 	p.playing = false
+	p.level = 1
 }
 
-// sendCommand sends a command to mpv via IPC socket
+// sendCommand sends a command to the current mpv via IPC socket
 func (p *Player) sendCommand(command []interface{}) error {
-	conn, err := net.DialTimeout("unix", p.socketPath, 100*time.Millisecond)
+	return send(p.socketPath, command)
+}
+
+// send sends a command to the mpv listening on socket
+func send(socket string, command []interface{}) error {
+	conn, err := net.DialTimeout("unix", socket, 100*time.Millisecond)
 	if err != nil {
 		return err
 	}
@@ -91,6 +184,61 @@ func (p *Player) sendCommand(command []interface{}) error {
 	return err
 }
 
+// setProperty sets an mpv property and waits for mpv to accept it
+func (p *Player) setProperty(name string, value interface{}) error {
+	return query(p.socketPath, []interface{}{"set_property", name, value}, nil)
+}
+
+// query sends a command to the current mpv and decodes the data of its
+// reply into result, a nil result only checks that the command succeeded
+func (p *Player) query(command []interface{}, result interface{}) error {
+	return query(p.socketPath, command, result)
+}
+
+// query sends a command to the mpv listening on socket and decodes the
+// data of its reply into result. Events arriving before the reply are
+// skipped.
+func query(socket string, command []interface{}, result interface{}) error {
+	conn, err := net.DialTimeout("unix", socket, 100*time.Millisecond)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Second))
+
+	msg := map[string]interface{}{
+		"command":    command,
+		"request_id": 1,
+	}
+	data, _ := json.Marshal(msg)
+	data = append(data, '\n')
+	if _, err := conn.Write(data); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(conn)
+	for {
+		var reply struct {
+			Data      json.RawMessage `json:"data"`
+			Error     string          `json:"error"`
+			RequestID *int            `json:"request_id"`
+		}
+		if err := dec.Decode(&reply); err != nil {
+			return err
+		}
+		if reply.RequestID == nil {
+			continue
+		}
+		if reply.Error != "success" {
+			return fmt.Errorf("mpv: %s", reply.Error)
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(reply.Data, result)
+	}
+}
+
 // SetVolume sets the playback volume (0-100)
 func (p *Player) SetVolume(vol int) {
 	p.mu.Lock()
@@ -105,7 +253,7 @@ func (p *Player) SetVolume(vol int) {
 	p.volume = vol
 
 	if p.playing {
-		p.sendCommand([]interface{}{"set_property", "volume", vol})
+		p.sendCommand([]interface{}{"set_property", "volume", scaleVolume(vol, p.level)})
 	}
 }
 
@@ -139,3 +287,17 @@ func (p *Player) CurrentURL() string {
 	defer p.mu.Unlock()
 	return p.streamURL
 }
+
+// Starts returns how many times mpv was started
+func (p *Player) Starts() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.starts
+}
+
+// Exits returns how many times the playing mpv exited on its own
+func (p *Player) Exits() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.exits
+}
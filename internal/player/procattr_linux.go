@@ -0,0 +1,20 @@
+package player
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// ConfigureCommand makes an mpv child die with this process. It runs in
+// its own process group, so terminal signals reach only the parent, and
+// the kernel kills it with SIGKILL when the parent exits, even on a crash.
+//
+// Pdeathsig fires when the thread that started the child exits. Go keeps
+// its threads for the life of the process unless a goroutine exits with
+// the thread locked, which this program never does.
+func ConfigureCommand(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid:   true,
+		Pdeathsig: syscall.SIGKILL,
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows && !linux
+
+package player
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// ConfigureCommand runs an mpv child in its own process group, so terminal
+// signals reach only the parent. There is no parent-death signal outside
+// Linux: orphans are stopped by CleanStale on the next start.
+func ConfigureCommand(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
@@ -0,0 +1,7 @@
+package player
+
+import "os/exec"
+
+// ConfigureCommand does nothing on Windows: orphans are stopped by
+// CleanStale on the next start
+func ConfigureCommand(cmd *exec.Cmd) {}
@@ -0,0 +1,25 @@
+//go:build !windows
+
+package player
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// alive reports whether a process with this PID is running. EPERM means
+// it exists but belongs to another user.
+func alive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// kill stops mpv together with anything it started in its process group
+func kill(cmd *exec.Cmd) {
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+	if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL); err != nil {
+		cmd.Process.Kill()
+	}
+}
@@ -0,0 +1,20 @@
+package player
+
+import (
+	"os"
+	"os/exec"
+)
+
+// alive reports whether a process with this PID is running
+func alive(pid int) bool {
+	_, err := os.FindProcess(pid)
+	return err == nil
+}
+
+// kill stops mpv
+func kill(cmd *exec.Cmd) {
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+	cmd.Process.Kill()
+}
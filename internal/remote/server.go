@@ -0,0 +1,368 @@
+// Package remote serves a local HTTP control API with a Server-Sent Events
+// stream of state changes and an embedded single-page web remote.
+package remote
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isalikov/radio-record-cli/internal/api"
+	"github.com/isalikov/radio-record-cli/internal/state"
+)
+
+//go:embed web
+var webFS embed.FS
+
+// stationsTTL is how long the station list is cached
+const stationsTTL = 10 * time.Minute
+
+// sseHeartbeat keeps idle event streams alive through proxies
+const sseHeartbeat = 15 * time.Second
+
+// Controller executes commands in the running player
+type Controller interface {
+	Play(stationID int)
+	Stop()
+	SetVolume(volume int)
+	ToggleFavorite(stationID int)
+}
+
+// StationLister loads the station list
+type StationLister interface {
+	GetStations() ([]api.Station, error)
+}
+
+// Server is the HTTP control API
+type Server struct {
+	token    string
+	store    *state.Store
+	control  Controller
+	stations StationLister
+	mux      *http.ServeMux
+
+	mu        sync.Mutex
+	cached    []api.Station
+	fetchedAt time.Time
+}
+
+// NewToken generates a random access token
+func NewToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func NewServer(token string, store *state.Store, control Controller, stations StationLister) *Server {
+	s := &Server{
+		token:    token,
+		store:    store,
+		control:  control,
+		stations: stations,
+		mux:      http.NewServeMux(),
+	}
+
+	web, _ := fs.Sub(webFS, "web")
+	s.mux.Handle("/", http.FileServer(http.FS(web)))
+	s.mux.HandleFunc("/api/stations", s.auth(s.handleStations))
+	s.mux.HandleFunc("/api/now", s.auth(s.handleNow))
+	s.mux.HandleFunc("/api/play", s.auth(s.handlePlay))
+	s.mux.HandleFunc("/api/stop", s.auth(s.handleStop))
+	s.mux.HandleFunc("/api/volume", s.auth(s.handleVolume))
+	s.mux.HandleFunc("/api/favorites/", s.auth(s.handleFavorite))
+	s.mux.HandleFunc("/api/events", s.auth(s.handleEvents))
+
+	return s
+}
+
+// Handle registers an additional token-protected handler, e.g. metrics
+func (s *Server) Handle(pattern string, h http.Handler) {
+	s.mux.HandleFunc(pattern, s.auth(h.ServeHTTP))
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe serves on addr until ctx is cancelled
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(_ net.Listener) context.Context { return ctx },
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// auth checks the token from the Authorization header or the token query
+// parameter (EventSource cannot send headers)
+func (s *Server) auth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" {
+			token = r.URL.Query().Get("token")
+		}
+		if s.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// stationJSON is a station in API responses
+type stationJSON struct {
+	ID       int      `json:"id"`
+	Prefix   string   `json:"prefix"`
+	Title    string   `json:"title"`
+	Tooltip  string   `json:"tooltip"`
+	Genres   []string `json:"genres"`
+	Favorite bool     `json:"favorite"`
+}
+
+func (s *Server) loadStations() ([]api.Station, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && time.Since(s.fetchedAt) < stationsTTL {
+		return s.cached, nil
+	}
+
+	stations, err := s.stations.GetStations()
+	if err != nil {
+		return nil, err
+	}
+	s.cached = stations
+	s.fetchedAt = time.Now()
+	return stations, nil
+}
+
+func (s *Server) findStation(id int, prefix string) (api.Station, bool) {
+	stations, err := s.loadStations()
+	if err != nil {
+		return api.Station{}, false
+	}
+	for _, st := range stations {
+		if (id != 0 && st.ID == id) || (prefix != "" && st.Prefix == prefix) {
+			return st, true
+		}
+	}
+	return api.Station{}, false
+}
+
+func (s *Server) handleStations(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	stations, err := s.loadStations()
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	favorites := make(map[int]bool)
+	for _, id := range s.store.Get().Favorites {
+		favorites[id] = true
+	}
+
+	result := make([]stationJSON, len(stations))
+	for i, st := range stations {
+		genres := make([]string, len(st.Genres))
+		for j, g := range st.Genres {
+			genres[j] = g.Name
+		}
+		result[i] = stationJSON{
+			ID:       st.ID,
+			Prefix:   st.Prefix,
+			Title:    st.Title,
+			Tooltip:  st.Tooltip,
+			Genres:   genres,
+			Favorite: favorites[st.ID],
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleNow(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.store.Get())
+}
+
+func (s *Server) handlePlay(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req struct {
+		ID     int    `json:"id"`
+		Prefix string `json:"prefix"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+
+	station, ok := s.findStation(req.ID, req.Prefix)
+	if !ok {
+		writeError(w, http.StatusNotFound, "station not found")
+		return
+	}
+
+	s.control.Play(station.ID)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) handleStop(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	s.control.Stop()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) handleVolume(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req struct {
+		Volume *int `json:"volume"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Volume == nil {
+		writeError(w, http.StatusBadRequest, `expected {"volume": 0-100}`)
+		return
+	}
+	if *req.Volume < 0 || *req.Volume > 100 {
+		writeError(w, http.StatusBadRequest, "volume must be 0-100")
+		return
+	}
+
+	s.control.SetVolume(*req.Volume)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// handleFavorite: PUT adds and DELETE removes /api/favorites/{id}
+func (s *Server) handleFavorite(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPut, http.MethodDelete) {
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/favorites/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid station id")
+		return
+	}
+	if _, ok := s.findStation(id, ""); !ok {
+		writeError(w, http.StatusNotFound, "station not found")
+		return
+	}
+
+	isFavorite := false
+	for _, fav := range s.store.Get().Favorites {
+		if fav == id {
+			isFavorite = true
+			break
+		}
+	}
+
+	if isFavorite != (r.Method == http.MethodPut) {
+		s.control.ToggleFavorite(id)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// handleEvents streams state changes as Server-Sent Events. The current
+// state is sent first as a "state" event.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+
+	events, unsubscribe := s.store.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	writeEvent(w, "state", s.store.Get())
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			writeEvent(w, e.Type, e.State)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, name string, data interface{}) {
+	payload, _ := json.Marshal(data)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, payload)
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if r.Method == m {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
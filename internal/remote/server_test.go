@@ -0,0 +1,177 @@
+package remote
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/isalikov/radio-record-cli/internal/api"
+	"github.com/isalikov/radio-record-cli/internal/state"
+)
+
+const testToken = "secret"
+
+type fakeController struct {
+	mu    sync.Mutex
+	calls []string
+	store *state.Store
+}
+
+func (f *fakeController) record(call string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, call)
+}
+
+func (f *fakeController) Play(id int) {
+	f.record("play " + strings.Repeat("*", id))
+	f.store.Update(func(s *state.State) { s.Playing = true; s.StationID = id })
+}
+func (f *fakeController) Stop()                 { f.record("stop") }
+func (f *fakeController) SetVolume(v int)       { f.record("volume") }
+func (f *fakeController) ToggleFavorite(id int) { f.record("favorite") }
+
+type fakeStations []api.Station
+
+func (f fakeStations) GetStations() ([]api.Station, error) { return f, nil }
+
+func newTestServer(t *testing.T) (*httptest.Server, *fakeController, *state.Store) {
+	store := state.NewStore("")
+	control := &fakeController{store: store}
+	stations := fakeStations{
+		{ID: 1, Prefix: "rr_main", Title: "Record", Genres: []api.Genre{{Name: "DANCE"}}},
+		{ID: 2, Prefix: "deep", Title: "Deep"},
+	}
+	srv := httptest.NewServer(NewServer(testToken, store, control, stations))
+	t.Cleanup(srv.Close)
+	return srv, control, store
+}
+
+func do(t *testing.T, method, url, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func TestAuth(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/api/now")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without token, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(srv.URL + "/api/now?token=" + testToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 with query token, got %d", resp.StatusCode)
+	}
+
+	// Web remote itself is public, it asks for the token
+	resp, err = http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Errorf("Expected web remote page, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+}
+
+func TestCommands(t *testing.T) {
+	srv, control, store := newTestServer(t)
+	store.Update(func(s *state.State) { s.Favorites = []int{2} })
+
+	tests := []struct {
+		method, path, body string
+		status             int
+	}{
+		{"POST", "/api/play", `{"prefix": "deep"}`, http.StatusAccepted},
+		{"POST", "/api/play", `{"id": 99}`, http.StatusNotFound},
+		{"GET", "/api/play", ``, http.StatusMethodNotAllowed},
+		{"POST", "/api/volume", `{"volume": 40}`, http.StatusAccepted},
+		{"POST", "/api/volume", `{"volume": 400}`, http.StatusBadRequest},
+		{"PUT", "/api/favorites/1", ``, http.StatusAccepted},
+		{"PUT", "/api/favorites/2", ``, http.StatusAccepted}, // already favorite
+		{"DELETE", "/api/favorites/x", ``, http.StatusBadRequest},
+		{"POST", "/api/stop", ``, http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		resp := do(t, tt.method, srv.URL+tt.path, tt.body)
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.path, tt.status, resp.StatusCode)
+		}
+	}
+
+	expected := []string{"play **", "volume", "favorite", "stop"}
+	if strings.Join(control.calls, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected calls %v, got %v", expected, control.calls)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	srv, _, store := newTestServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+"/api/events?token="+testToken, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected event stream, got %s", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() string {
+		var name string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("Reading event failed: %v", err)
+			}
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "event: ") {
+				name = strings.TrimPrefix(line, "event: ")
+			}
+			if line == "" && name != "" {
+				return name
+			}
+		}
+	}
+
+	if name := readEvent(); name != "state" {
+		t.Errorf("Expected initial state event, got %s", name)
+	}
+
+	store.Update(func(s *state.State) { s.Volume = 55 })
+	if name := readEvent(); name != state.EventVolumeChange {
+		t.Errorf("Expected volume_change event, got %s", name)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+
+package rundir
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// checkOwner fails if the directory belongs to another user
+func checkOwner(info os.FileInfo) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	if int(stat.Uid) != os.Getuid() {
+		return errors.New("owned by another user")
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// Package rundir locates the private runtime directory shared by the state
+// file and the mpv sockets.
+package rundir
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Dir returns $XDG_RUNTIME_DIR/radio-record-cli, or a per-user directory in
+// the system temp dir when XDG_RUNTIME_DIR is not set
+func Dir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "radio-record-cli")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("radio-record-cli-%d", os.Getuid()))
+}
+
+// Ensure creates Dir with 0700 permissions so other users cannot control
+// the player. A directory in the shared temp dir may have been created by
+// someone else in advance: it is rejected unless it is a real directory
+// owned by the current user.
+func Ensure() (string, error) {
+	dir := Dir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("runtime directory %s is not a directory", dir)
+	}
+	if err := checkOwner(info); err != nil {
+		return "", fmt.Errorf("runtime directory %s: %w", dir, err)
+	}
+
+	// MkdirAll keeps the mode of an existing directory
+	if info.Mode().Perm() != 0700 {
+		if err := os.Chmod(dir, 0700); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
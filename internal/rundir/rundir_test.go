@@ -0,0 +1,45 @@
+package rundir
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnsure(t *testing.T) {
+	runtime := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtime)
+
+	// An existing directory with wider permissions is tightened
+	expected := filepath.Join(runtime, "radio-record-cli")
+	os.Mkdir(expected, 0755)
+
+	dir, err := Ensure()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != expected {
+		t.Errorf("Expected %s, got %s", expected, dir)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("Expected mode 0700, got %o", info.Mode().Perm())
+	}
+}
+
+func TestEnsureRejectsSymlink(t *testing.T) {
+	runtime := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtime)
+
+	// A link planted in place of the directory must not be followed
+	target := t.TempDir()
+	if err := os.Symlink(target, filepath.Join(runtime, "radio-record-cli")); err != nil {
+		t.Skip(err)
+	}
+	if _, err := Ensure(); err == nil {
+		t.Errorf("Expected a symlink to be rejected")
+	}
+}
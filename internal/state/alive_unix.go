@@ -0,0 +1,20 @@
+//go:build !windows
+
+package state
+
+import (
+	"os"
+	"syscall"
+)
+
+// Alive reports whether the process that wrote the state is still running
+func (st State) Alive() bool {
+	if st.PID <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(st.PID)
+	if err != nil {
+		return false
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,12 @@
+package state
+
+import "os"
+
+// Alive reports whether the process that wrote the state is still running
+func (st State) Alive() bool {
+	if st.PID <= 0 {
+		return false
+	}
+	_, err := os.FindProcess(st.PID)
+	return err == nil
+}
@@ -0,0 +1,285 @@
+// Package state shares what a running instance is playing with other
+// processes (status bars) and in-process consumers (hooks, HTTP API).
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/isalikov/radio-record-cli/internal/rundir"
+)
+
+// State is a snapshot of the player state
+type State struct {
+	PID       int       `json:"pid"`
+	Playing   bool      `json:"playing"`
+	StationID int       `json:"station_id,omitempty"`
+	Station   string    `json:"station,omitempty"`
+	Prefix    string    `json:"prefix,omitempty"`
+	TrackID   int       `json:"track_id,omitempty"`
+	Artist    string    `json:"artist,omitempty"`
+	Song      string    `json:"song,omitempty"`
+	Image     string    `json:"image,omitempty"`
+	Volume    int       `json:"volume"`
+	Favorites []int     `json:"favorites,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Event types
+const (
+	EventPlay         = "play"
+	EventStop         = "stop"
+	EventTrackChange  = "track_change"
+	EventVolumeChange = "volume_change"
+	EventError        = "error"
+	EventFavorites    = "favorites_change"
+)
+
+// Event is a state change
+type Event struct {
+	Type  string `json:"type"`
+	State State  `json:"state"`
+	Prev  State  `json:"-"`
+}
+
+// subscriberBuffer is the number of events kept for a slow subscriber.
+// Events beyond it are dropped rather than blocking the UI.
+const subscriberBuffer = 32
+
+// Store holds the current state, mirrors it to a file and notifies
+// subscribers about changes. A nil *Store is valid and does nothing.
+type Store struct {
+	mu    sync.Mutex
+	state State
+	path  string
+	subs  map[chan Event]struct{}
+}
+
+// NewStore creates a store that mirrors state to path. Empty path
+// disables the state file.
+func NewStore(path string) *Store {
+	return &Store{
+		state: State{PID: os.Getpid()},
+		path:  path,
+		subs:  make(map[chan Event]struct{}),
+	}
+}
+
+// DefaultPath returns the state file location in the private runtime
+// directory, see rundir.Dir
+func DefaultPath() string {
+	return filepath.Join(rundir.Dir(), "state.json")
+}
+
+// Get returns the current state
+func (s *Store) Get() State {
+	if s == nil {
+		return State{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// Update applies fn to the state, writes the state file and sends events
+// derived from the difference with the previous state.
+func (s *Store) Update(fn func(*State)) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	prev := s.state
+	fn(&s.state)
+	s.state.Error = ""
+	s.state.UpdatedAt = time.Now()
+	cur := s.state
+	s.write()
+	s.mu.Unlock()
+
+	for _, t := range diff(prev, cur) {
+		s.publish(Event{Type: t, State: cur, Prev: prev})
+	}
+}
+
+// Fail reports an error event without changing the playback state
+func (s *Store) Fail(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.mu.Lock()
+	prev := s.state
+	s.state.Error = err.Error()
+	s.state.UpdatedAt = time.Now()
+	cur := s.state
+	s.write()
+	s.mu.Unlock()
+
+	s.publish(Event{Type: EventError, State: cur, Prev: prev})
+}
+
+func diff(prev, cur State) []string {
+	var events []string
+	switch {
+	case cur.Playing && (!prev.Playing || prev.StationID != cur.StationID):
+		events = append(events, EventPlay)
+	case !cur.Playing && prev.Playing:
+		events = append(events, EventStop)
+	}
+	if cur.Playing && cur.TrackID != 0 && cur.TrackID != prev.TrackID {
+		events = append(events, EventTrackChange)
+	}
+	if cur.Volume != prev.Volume {
+		events = append(events, EventVolumeChange)
+	}
+	if !equalInts(cur.Favorites, prev.Favorites) {
+		events = append(events, EventFavorites)
+	}
+	return events
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Subscribe returns a channel of events and a function to unsubscribe
+func (s *Store) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+	if s == nil {
+		return ch, func() {}
+	}
+
+	s.mu.Lock()
+	s.subs[ch] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subs, ch)
+			s.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+func (s *Store) publish(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// write mirrors the state to the file atomically. Called with s.mu held.
+func (s *Store) write() {
+	if s.path == "" {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return
+	}
+
+	data, err := json.Marshal(s.state)
+	if err != nil {
+		return
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return
+	}
+	os.Rename(tmp, s.path)
+}
+
+// Close removes the state file if it still belongs to this process
+func (s *Store) Close() {
+	if s == nil || s.path == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if st, err := Read(s.path); err == nil && st.PID == s.state.PID {
+		os.Remove(s.path)
+	}
+}
+
+// Read loads a state file written by a running instance
+func Read(path string) (State, error) {
+	var st State
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return st, err
+	}
+	err = json.Unmarshal(data, &st)
+	return st, err
+}
+
+// Format renders the state using placeholders {station}, {prefix},
+// {artist}, {song}, {track} (artist — song), {volume} and {status}.
+func (st State) Format(format string) string {
+	status := "■"
+	if st.Playing {
+		status = "▶"
+	}
+
+	track := ""
+	if st.Song != "" || st.Artist != "" {
+		track = st.Artist + " — " + st.Song
+	}
+
+	r := strings.NewReplacer(
+		"{station}", st.Station,
+		"{prefix}", st.Prefix,
+		"{artist}", st.Artist,
+		"{song}", st.Song,
+		"{track}", track,
+		"{volume}", fmt.Sprint(st.Volume),
+		"{status}", status,
+	)
+	return strings.TrimSpace(r.Replace(format))
+}
+
+// waybarOutput is the JSON format of waybar custom modules
+type waybarOutput struct {
+	Text       string `json:"text"`
+	Tooltip    string `json:"tooltip"`
+	Class      string `json:"class"`
+	Alt        string `json:"alt"`
+	Percentage int    `json:"percentage"`
+}
+
+// Waybar renders the state as a waybar custom module line
+func (st State) Waybar(format string) ([]byte, error) {
+	out := waybarOutput{Class: "stopped", Alt: "stopped", Percentage: st.Volume}
+	if st.Playing {
+		out.Text = st.Format(format)
+		out.Tooltip = st.Format("{station}\n{track}")
+		out.Class = "playing"
+		out.Alt = "playing"
+	}
+	return json.Marshal(out)
+}
@@ -0,0 +1,120 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoreEvents(t *testing.T) {
+	store := NewStore("")
+	events, unsubscribe := store.Subscribe()
+	defer unsubscribe()
+
+	store.Update(func(s *State) {
+		s.Playing = true
+		s.StationID = 1
+		s.Station = "Record"
+	})
+	store.Update(func(s *State) {
+		s.TrackID = 10
+		s.Artist = "Artist"
+		s.Song = "Song"
+	})
+	store.Update(func(s *State) { s.TrackID = 10 }) // no change
+	store.Update(func(s *State) { s.Volume = 50 })
+	store.Fail(errors.New("mpv crashed"))
+	store.Update(func(s *State) { s.Playing = false })
+
+	expected := []string{EventPlay, EventTrackChange, EventVolumeChange, EventError, EventStop}
+	for _, want := range expected {
+		e := <-events
+		if e.Type != want {
+			t.Errorf("Expected event %s, got %s", want, e.Type)
+		}
+	}
+
+	select {
+	case e := <-events:
+		t.Errorf("Unexpected event %s", e.Type)
+	default:
+	}
+}
+
+func TestStoreFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "state.json")
+	store := NewStore(path)
+
+	store.Update(func(s *State) {
+		s.Playing = true
+		s.Station = "Deep"
+		s.Volume = 70
+	})
+
+	st, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if st.Station != "Deep" || st.Volume != 70 || st.PID != os.Getpid() {
+		t.Errorf("Unexpected state in file: %+v", st)
+	}
+	if !st.Alive() {
+		t.Error("Expected own process to be alive")
+	}
+
+	info, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("Expected state dir mode 0700, got %o", info.Mode().Perm())
+	}
+
+	store.Close()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected state file to be removed on Close")
+	}
+}
+
+func TestNilStore(t *testing.T) {
+	var store *Store
+	store.Update(func(s *State) { s.Playing = true })
+	store.Fail(errors.New("ignored"))
+	store.Close()
+
+	if st := store.Get(); st.Playing {
+		t.Error("Nil store should return zero state")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	st := State{Playing: true, Station: "Record", Artist: "JOHN SUMMIT", Song: "light years", Volume: 80}
+
+	got := st.Format("{station} · {artist} — {song} {volume}%")
+	if got != "Record · JOHN SUMMIT — light years 80%" {
+		t.Errorf("Unexpected format result: %q", got)
+	}
+
+	if got := (State{}).Format("{status} {track}"); got != "■" {
+		t.Errorf("Unexpected stopped format result: %q", got)
+	}
+}
+
+func TestWaybar(t *testing.T) {
+	st := State{Playing: true, Station: "Record", Artist: "A", Song: "B", Volume: 40}
+
+	data, err := st.Waybar("{artist} — {song}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out waybarOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Text != "A — B" || out.Class != "playing" || out.Tooltip != "Record\nA — B" || out.Percentage != 40 {
+		t.Errorf("Unexpected waybar output: %s", data)
+	}
+}
@@ -0,0 +1,141 @@
+// Package stats keeps per-station listening statistics: play counts,
+// listening time and when a station was last played.
+package stats
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/isalikov/radio-record-cli/internal/state"
+)
+
+// Station is the listening statistics of a single station
+type Station struct {
+	Plays      int       `json:"plays"`
+	Seconds    float64   `json:"seconds"`
+	LastPlayed time.Time `json:"last_played"`
+}
+
+// Store holds statistics by station ID and persists them to a file.
+// A nil *Store is valid and empty.
+type Store struct {
+	mu       sync.Mutex
+	path     string
+	stations map[int]Station
+
+	playing int // ID of the station being listened to, 0 if none
+	since   time.Time
+}
+
+// DefaultPath returns stats.json next to the config
+func DefaultPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = os.Getenv("HOME")
+	}
+	return filepath.Join(configDir, "radio-record-cli", "stats.json")
+}
+
+// Load reads statistics from path. A missing file gives empty statistics.
+func Load(path string) *Store {
+	s := &Store{path: path, stations: map[int]Station{}}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &s.stations)
+	}
+	if s.stations == nil {
+		s.stations = map[int]Station{}
+	}
+	return s
+}
+
+// Get returns statistics of a station
+func (s *Store) Get(id int) Station {
+	if s == nil {
+		return Station{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stations[id]
+}
+
+// RecordPlay counts a station start at the given time and closes the
+// listening interval of the previous station
+func (s *Store) RecordPlay(id int, at time.Time) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.finish(at)
+	st := s.stations[id]
+	st.Plays++
+	st.LastPlayed = at
+	s.stations[id] = st
+	s.playing = id
+	s.since = at
+	s.save()
+}
+
+// RecordStop closes the current listening interval
+func (s *Store) RecordStop(at time.Time) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.finish(at)
+	s.save()
+}
+
+// finish adds the time since the last start to the playing station.
+// Called with s.mu held.
+func (s *Store) finish(at time.Time) {
+	if s.playing == 0 {
+		return
+	}
+	st := s.stations[s.playing]
+	st.Seconds += at.Sub(s.since).Seconds()
+	st.LastPlayed = at
+	s.stations[s.playing] = st
+	s.playing = 0
+}
+
+// Run records play and stop events from the state store until the
+// returned stop function is called
+func (s *Store) Run(store *state.Store) (stop func()) {
+	events, unsubscribe := store.Subscribe()
+	go func() {
+		for e := range events {
+			switch e.Type {
+			case state.EventPlay:
+				s.RecordPlay(e.State.StationID, e.State.UpdatedAt)
+			case state.EventStop:
+				s.RecordStop(e.State.UpdatedAt)
+			}
+		}
+	}()
+	return func() {
+		unsubscribe()
+		s.RecordStop(time.Now())
+	}
+}
+
+// save writes statistics to the file. Called with s.mu held.
+func (s *Store) save() {
+	if s.path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(s.stations, "", "  ")
+	if err != nil {
+		return
+	}
+	os.WriteFile(s.path, data, 0644)
+}
@@ -0,0 +1,39 @@
+package stats
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	s := Load(path)
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.RecordPlay(1, start)
+	s.RecordPlay(2, start.Add(time.Minute)) // switching closes station 1
+	s.RecordStop(start.Add(3 * time.Minute))
+	s.RecordPlay(1, start.Add(time.Hour))
+	s.RecordStop(start.Add(time.Hour + 30*time.Second))
+
+	// Reload from disk
+	s = Load(path)
+
+	st := s.Get(1)
+	if st.Plays != 2 || st.Seconds != 90 {
+		t.Errorf("Expected 2 plays and 90s for station 1, got %+v", st)
+	}
+	if !st.LastPlayed.Equal(start.Add(time.Hour + 30*time.Second)) {
+		t.Errorf("Unexpected last played %v", st.LastPlayed)
+	}
+
+	if st := s.Get(2); st.Plays != 1 || st.Seconds != 120 {
+		t.Errorf("Expected 1 play and 120s for station 2, got %+v", st)
+	}
+
+	var empty *Store
+	if st := empty.Get(1); st.Plays != 0 {
+		t.Errorf("Expected empty stats from nil store, got %+v", st)
+	}
+}
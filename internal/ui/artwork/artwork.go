@@ -0,0 +1,125 @@
+// Package artwork renders album covers in the terminal using the kitty
+// graphics protocol, iTerm2 inline images, sixel or Unicode half blocks.
+package artwork
+
+import (
+	"image"
+	"strings"
+)
+
+// Protocol is a way to draw images in a terminal
+type Protocol int
+
+const (
+	None Protocol = iota
+	HalfBlock
+	Kitty
+	ITerm2
+	Sixel
+)
+
+var protocolNames = map[Protocol]string{
+	None:      "off",
+	HalfBlock: "blocks",
+	Kitty:     "kitty",
+	ITerm2:    "iterm2",
+	Sixel:     "sixel",
+}
+
+func (p Protocol) String() string {
+	return protocolNames[p]
+}
+
+// ParseProtocol parses a protocol name from config. Empty string and
+// "auto" return ok=false, meaning the protocol should be detected.
+func ParseProtocol(name string) (Protocol, bool) {
+	for p, n := range protocolNames {
+		if n == name {
+			return p, true
+		}
+	}
+	return None, false
+}
+
+// Detect guesses the best supported protocol from the environment.
+// Inside tmux and screen graphics passthrough is unreliable, so half
+// blocks are used there.
+func Detect(getenv func(string) string) Protocol {
+	term := getenv("TERM")
+	program := getenv("TERM_PROGRAM")
+
+	switch {
+	case getenv("TMUX") != "" || strings.HasPrefix(term, "screen"):
+		return HalfBlock
+	case getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || program == "ghostty":
+		return Kitty
+	case program == "iTerm.app" || program == "WezTerm" || getenv("LC_TERMINAL") == "iTerm2":
+		return ITerm2
+	case term == "foot" || strings.HasPrefix(term, "foot-") || strings.HasPrefix(term, "mlterm") ||
+		getenv("KONSOLE_VERSION") != "" || strings.Contains(term, "sixel"):
+		return Sixel
+	case term == "dumb" || term == "":
+		return None
+	}
+	return HalfBlock
+}
+
+// Cell size in pixels used to size raster images for sixel. Terminals
+// rarely report it, so a common default is assumed.
+const (
+	cellWidth  = 8
+	cellHeight = 16
+)
+
+// Renderer turns images into strings that draw them in cols×rows cells.
+// Every line of the result has a display width of exactly cols, so it can
+// be joined with other lipgloss blocks.
+type Renderer struct {
+	Protocol Protocol
+}
+
+// Render draws img in a cols×rows cell area. Returns "" for None.
+func (r Renderer) Render(img image.Image, cols, rows int) string {
+	if img == nil || cols <= 0 || rows <= 0 {
+		return ""
+	}
+
+	switch r.Protocol {
+	case HalfBlock:
+		return renderHalfBlock(img, cols, rows)
+	case Kitty:
+		return withPlaceholder(encodeKitty(resize(img, cols*cellWidth, rows*cellHeight), cols, rows), cols, rows)
+	case ITerm2:
+		return withPlaceholder(encodeITerm2(resize(img, cols*cellWidth, rows*cellHeight), cols, rows), cols, rows)
+	case Sixel:
+		return withPlaceholder(encodeSixel(resize(img, cols*cellWidth, rows*cellHeight)), cols, rows)
+	}
+	return ""
+}
+
+// withPlaceholder puts an escape sequence that draws an image without
+// moving the cursor in front of a block of spaces reserving its cells.
+func withPlaceholder(seq string, cols, rows int) string {
+	blank := strings.Repeat(" ", cols)
+	lines := make([]string, rows)
+	for i := range lines {
+		lines[i] = blank
+	}
+	lines[0] = saveCursor + seq + restoreCursor + blank
+	return strings.Join(lines, "\n")
+}
+
+const (
+	saveCursor    = "\x1b7"
+	restoreCursor = "\x1b8"
+)
+
+// Clear returns a sequence that removes images drawn outside the text grid.
+// Only kitty keeps images on a separate layer; for other protocols the
+// text drawn over the cells replaces the image.
+func Clear(p Protocol) string {
+	if p == Kitty {
+		return "\x1b_Ga=d,d=a,q=2\x1b\\"
+	}
+	return ""
+}
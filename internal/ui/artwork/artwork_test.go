@@ -0,0 +1,204 @@
+package artwork
+
+import (
+	"encoding/base64"
+	"image"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func loadFixture(t *testing.T, name string) image.Image {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("Open fixture failed: %v", err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		t.Fatalf("Decode fixture failed: %v", err)
+	}
+	return img
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		env      map[string]string
+		expected Protocol
+	}{
+		{map[string]string{"TERM": "xterm-kitty"}, Kitty},
+		{map[string]string{"TERM": "xterm-256color", "KITTY_WINDOW_ID": "1"}, Kitty},
+		{map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "iTerm.app"}, ITerm2},
+		{map[string]string{"TERM": "foot"}, Sixel},
+		{map[string]string{"TERM": "xterm-256color"}, HalfBlock},
+		{map[string]string{"TERM": "xterm-kitty", "TMUX": "/tmp/tmux-1000/default,1,0"}, HalfBlock},
+		{map[string]string{"TERM": "dumb"}, None},
+	}
+
+	for _, tt := range tests {
+		got := Detect(func(k string) string { return tt.env[k] })
+		if got != tt.expected {
+			t.Errorf("Detect(%v) = %s, expected %s", tt.env, got, tt.expected)
+		}
+	}
+}
+
+func TestParseProtocol(t *testing.T) {
+	if p, ok := ParseProtocol("sixel"); !ok || p != Sixel {
+		t.Errorf("Expected sixel, got %s (ok=%v)", p, ok)
+	}
+	if _, ok := ParseProtocol("auto"); ok {
+		t.Error("Expected auto to require detection")
+	}
+}
+
+func TestRenderHalfBlock(t *testing.T) {
+	img := loadFixture(t, "quadrants.png")
+
+	out := Renderer{Protocol: HalfBlock}.Render(img, 2, 1)
+
+	// Upper row is red/green, lower row is blue/white
+	expected := "\x1b[38;2;255;0;0m\x1b[48;2;0;0;255m▀" +
+		"\x1b[38;2;0;255;0m\x1b[48;2;255;255;255m▀\x1b[0m"
+	if out != expected {
+		t.Errorf("Unexpected half-block output:\n%q\nexpected:\n%q", out, expected)
+	}
+}
+
+// Every protocol must produce a block of exactly cols×rows cells,
+// otherwise it breaks layout when joined with the now-playing box.
+func TestRenderSize(t *testing.T) {
+	img := loadFixture(t, "cover100.jpg")
+
+	for _, p := range []Protocol{HalfBlock, Kitty, ITerm2, Sixel} {
+		out := Renderer{Protocol: p}.Render(img, 12, 6)
+		lines := strings.Split(out, "\n")
+		if len(lines) != 6 {
+			t.Errorf("%s: expected 6 lines, got %d", p, len(lines))
+		}
+		for i, line := range lines {
+			if w := lipgloss.Width(line); w != 12 {
+				t.Errorf("%s: line %d has width %d, expected 12", p, i, w)
+			}
+		}
+	}
+
+	if out := (Renderer{Protocol: None}).Render(img, 12, 6); out != "" {
+		t.Errorf("Expected empty output for None, got %q", out)
+	}
+}
+
+func TestEncodeKittyChunks(t *testing.T) {
+	img := loadFixture(t, "cover100.jpg")
+
+	out := encodeKitty(img, 12, 6)
+	if !strings.HasPrefix(out, "\x1b_Ga=d,d=a,q=2\x1b\\\x1b_Ga=T,f=100,c=12,r=6,C=1,q=2,m=") {
+		t.Errorf("Unexpected kitty header: %q", out[:60])
+	}
+	if !strings.HasSuffix(out, "\x1b\\") || !strings.Contains(out, "\x1b_Gm=0;") {
+		t.Error("Expected last kitty chunk with m=0")
+	}
+
+	for _, chunk := range strings.Split(out, "\x1b\\") {
+		if _, payload, ok := strings.Cut(chunk, ";"); ok && len(payload) > kittyChunk {
+			t.Errorf("Chunk payload too large: %d", len(payload))
+		}
+	}
+}
+
+func TestEncodeITerm2(t *testing.T) {
+	img := loadFixture(t, "quadrants.png")
+
+	out := encodeITerm2(img, 4, 2)
+	if !strings.HasPrefix(out, "\x1b]1337;File=inline=1;") || !strings.HasSuffix(out, "\a") {
+		t.Errorf("Unexpected iTerm2 sequence: %q", out)
+	}
+
+	data := out[strings.Index(out, ":")+1 : len(out)-1]
+	if _, err := base64.StdEncoding.DecodeString(data); err != nil {
+		t.Errorf("Payload is not valid base64: %v", err)
+	}
+}
+
+func TestEncodeSixel(t *testing.T) {
+	img := loadFixture(t, "quadrants.png")
+
+	out := encodeSixel(img)
+	if !strings.HasPrefix(out, "\x1bP0;1;0q\"1;1;4;4") || !strings.HasSuffix(out, "\x1b\\") {
+		t.Errorf("Unexpected sixel sequence: %q", out)
+	}
+	// Red, green, blue and white from the color cube
+	for _, color := range []string{"#180;2;100;0;0", "#30;2;0;100;0", "#5;2;0;0;100", "#215;2;100;100;100"} {
+		if !strings.Contains(out, color) {
+			t.Errorf("Expected palette entry %s in %q", color, out)
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	data, err := os.ReadFile("testdata/cover100.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	cache := NewCache(t.TempDir())
+	for i := 0; i < 2; i++ {
+		img, err := cache.Load(server.URL + "/cover.jpg")
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if img.Bounds().Dx() != 100 {
+			t.Errorf("Expected 100px wide image, got %d", img.Bounds().Dx())
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected a single download, got %d", requests)
+	}
+}
+
+func TestCachePrune(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, 100))
+	}))
+	defer server.Close()
+
+	cache := NewCache(t.TempDir())
+	cache.limit = 250
+
+	old := time.Now().Add(-time.Hour)
+	for _, name := range []string{"/a", "/b"} {
+		path, err := cache.File(server.URL + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, old, old)
+		old = old.Add(time.Minute)
+	}
+
+	// a used again, so b is the least recently used
+	cache.File(server.URL + "/a")
+	if _, err := cache.File(server.URL + "/c"); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, kept := range map[string]bool{"/a": true, "/b": false, "/c": true} {
+		if _, err := os.Stat(cache.path(server.URL + name)); (err == nil) != kept {
+			t.Errorf("%s: expected kept=%v, got %v", name, kept, err)
+		}
+	}
+}
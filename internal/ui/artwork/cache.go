@@ -0,0 +1,142 @@
+package artwork
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/jpeg" // covers from the API are JPEG
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// maxImageSize limits downloaded covers
+const maxImageSize = 5 << 20
+
+// maxCacheSize limits the covers kept on disk. Least recently used covers
+// are removed once the cache grows past it.
+const maxCacheSize = 50 << 20
+
+// Cache downloads cover images and keeps them on disk
+type Cache struct {
+	dir   string
+	http  *http.Client
+	limit int64
+}
+
+// NewCache creates a cache in dir. An empty dir selects the user cache directory.
+func NewCache(dir string) *Cache {
+	if dir == "" {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			base = os.TempDir()
+		}
+		dir = filepath.Join(base, "radio-record-cli", "covers")
+	}
+	return &Cache{
+		dir:   dir,
+		http:  &http.Client{Timeout: 10 * time.Second},
+		limit: maxCacheSize,
+	}
+}
+
+func (c *Cache) path(url string) string {
+	sum := sha1.Sum([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// File returns the path of the cached image for url, downloading it on first use
+func (c *Cache) File(url string) (string, error) {
+	path := c.path(url)
+	if _, err := os.Stat(path); err == nil {
+		// The modification time marks the last use for prune
+		now := time.Now()
+		os.Chtimes(path, now, now)
+		return path, nil
+	}
+
+	data, err := c.download(url)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", err
+	}
+	c.prune()
+	return path, nil
+}
+
+// prune removes the least recently used covers until the cache fits
+// into its limit
+func (c *Cache) prune() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	var files []os.FileInfo
+	var total int64
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, info)
+		total += info.Size()
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+	for _, f := range files {
+		if total <= c.limit {
+			break
+		}
+		if os.Remove(filepath.Join(c.dir, f.Name())) == nil {
+			total -= f.Size()
+		}
+	}
+}
+
+// Load returns the decoded image for url, downloading it on first use
+func (c *Cache) Load(url string) (image.Image, error) {
+	path, err := c.File(url)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return img, nil
+}
+
+func (c *Cache) download(url string) ([]byte, error) {
+	resp, err := c.http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cover download: %s", resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxImageSize))
+}
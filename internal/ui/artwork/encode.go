@@ -0,0 +1,201 @@
+package artwork
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+)
+
+// kittyChunk is the maximum payload size of a single kitty graphics command
+const kittyChunk = 4096
+
+// encodeKitty transmits and displays a PNG with the kitty graphics protocol.
+// Previous placements are deleted first so covers don't pile up; q=2
+// suppresses terminal responses that would otherwise arrive as key input.
+func encodeKitty(img image.Image, cols, rows int) string {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return ""
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	var b strings.Builder
+	b.WriteString(Clear(Kitty))
+
+	for i := 0; i < len(data); i += kittyChunk {
+		end := i + kittyChunk
+		more := 1
+		if end >= len(data) {
+			end = len(data)
+			more = 0
+		}
+
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,c=%d,r=%d,C=1,q=2,m=%d;%s\x1b\\", cols, rows, more, data[i:end])
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, data[i:end])
+		}
+	}
+
+	return b.String()
+}
+
+// encodeITerm2 displays a PNG with the iTerm2 inline image protocol
+func encodeITerm2(img image.Image, cols, rows int) string {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return ""
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=0:%s\a",
+		buf.Len(), cols, rows, data)
+}
+
+// renderHalfBlock draws two pixel rows per cell with "▀": the upper pixel
+// is the foreground color and the lower one is the background.
+func renderHalfBlock(img image.Image, cols, rows int) string {
+	scaled := resize(img, cols, rows*2)
+
+	var b strings.Builder
+	for y := 0; y < rows; y++ {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		for x := 0; x < cols; x++ {
+			tr, tg, tb := rgb8(scaled.At(x, y*2))
+			br, bg, bb := rgb8(scaled.At(x, y*2+1))
+			fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀", tr, tg, tb, br, bg, bb)
+		}
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+// encodeSixel encodes an image as sixel graphics using a fixed 6×6×6 color cube
+func encodeSixel(img image.Image) string {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	// Palette index for every pixel
+	pixels := make([]int, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r, g, b := rgb8(img.At(bounds.Min.X+x, bounds.Min.Y+y))
+			pixels[y*w+x] = int(r)*6/256*36 + int(g)*6/256*6 + int(b)*6/256
+		}
+	}
+
+	var b strings.Builder
+	// DCS, 1:1 aspect ratio, raster attributes
+	fmt.Fprintf(&b, "\x1bP0;1;0q\"1;1;%d;%d", w, h)
+
+	used := make(map[int]bool)
+	for _, p := range pixels {
+		used[p] = true
+	}
+	for i := 0; i < 216; i++ {
+		if !used[i] {
+			continue
+		}
+		// Sixel colors are in percent
+		r, g, bl := i/36*100/5, i/6%6*100/5, i%6*100/5
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, r, g, bl)
+	}
+
+	for band := 0; band < h; band += 6 {
+		first := true
+		for c := 0; c < 216; c++ {
+			if !used[c] {
+				continue
+			}
+
+			row := make([]byte, w)
+			present := false
+			for x := 0; x < w; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && band+dy < h; dy++ {
+					if pixels[(band+dy)*w+x] == c {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = '?' + bits
+				present = present || bits != 0
+			}
+			if !present {
+				continue
+			}
+
+			if !first {
+				b.WriteByte('$') // carriage return within the band
+			}
+			first = false
+			fmt.Fprintf(&b, "#%d", c)
+			writeSixelRLE(&b, row)
+		}
+		b.WriteByte('-') // next band
+	}
+
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+// writeSixelRLE writes a sixel row with run-length compression
+func writeSixelRLE(b *strings.Builder, row []byte) {
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(b, "!%d%c", n, row[i])
+		} else {
+			b.WriteString(strings.Repeat(string(row[i]), n))
+		}
+		i = j
+	}
+}
+
+// resize scales an image to w×h with box filtering (averaging source pixels)
+func resize(img image.Image, w, h int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	src := img.Bounds()
+	sw, sh := src.Dx(), src.Dy()
+
+	for y := 0; y < h; y++ {
+		y0 := src.Min.Y + y*sh/h
+		y1 := src.Min.Y + (y+1)*sh/h
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < w; x++ {
+			x0 := src.Min.X + x*sw/w
+			x1 := src.Min.X + (x+1)*sw/w
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb := rgb8(img.At(sx, sy))
+					r += uint32(pr)
+					g += uint32(pg)
+					b += uint32(pb)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA{uint8(r / n), uint8(g / n), uint8(b / n), 255})
+		}
+	}
+	return dst
+}
+
+func rgb8(c color.Color) (uint8, uint8, uint8) {
+	r, g, b, _ := c.RGBA()
+	return uint8(r >> 8), uint8(g >> 8), uint8(b >> 8)
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// copyToClipboard копирует текст через OSC 52 — это работает и по SSH,
+// если терминал поддерживает. Локально текст дополнительно кладётся
+// в системный буфер обмена, если найдена подходящая утилита.
+func copyToClipboard(text string) error {
+	oscErr := copyOSC52(text)
+
+	if os.Getenv("SSH_TTY") != "" {
+		return oscErr
+	}
+
+	name, args := clipboardCommand()
+	if name == "" {
+		return oscErr
+	}
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(text)
+	if err := cmd.Run(); err != nil && oscErr != nil {
+		return err
+	}
+	return nil
+}
+
+func copyOSC52(text string) error {
+	seq := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+
+	// Пишем прямо в терминал: stdout занят Bubble Tea
+	var w io.Writer = os.Stderr
+	if tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0); err == nil {
+		defer tty.Close()
+		w = tty
+	}
+
+	_, err := seq.WriteTo(w)
+	return err
+}
+
+// clipboardCommand подбирает утилиту системного буфера обмена
+func clipboardCommand() (string, []string) {
+	candidates := [][]string{}
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = append(candidates, []string{"pbcopy"})
+	case "windows":
+		candidates = append(candidates, []string{"clip"})
+	default:
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"wl-copy"})
+		}
+		candidates = append(candidates,
+			[]string{"xclip", "-selection", "clipboard"},
+			[]string{"xsel", "--clipboard", "--input"},
+		)
+	}
+
+	for _, c := range candidates {
+		if _, err := exec.LookPath(c[0]); err == nil {
+			return c[0], c[1:]
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,660 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/isalikov/radio-record-cli/internal/api"
+	"github.com/isalikov/radio-record-cli/internal/config"
+)
+
+// action — именованная команда. Её вызывают клавиши (через keymap),
+// палитра «:» и аргументы командной строки.
+type action struct {
+	name     string
+	args     string // подсказка по аргументам
+	help     string
+	run      func(m *Model, args []string) tea.Cmd
+	complete func(m *Model, args []string) []string // варианты последнего аргумента для Tab
+}
+
+// actions заполняется в init: команды ссылаются на функции, которые
+// сами обращаются к списку
+var actions []action
+
+// keymap связывает клавиши обычного режима с командами
+var keymap = map[string]string{
+	"ctrl+c":    "quit",
+	"q":         "quit",
+	":":         "palette",
+	"/":         "search",
+	"?":         "help",
+	"esc":       "clear",
+	"n":         "next",
+	"N":         "prev",
+	"up":        "up",
+	"k":         "up",
+	"down":      "down",
+	"j":         "down",
+	"g":         "top",
+	"G":         "bottom",
+	"enter":     "toggle",
+	" ":         "toggle",
+	"s":         "stop",
+	"+":         "vol +5",
+	"=":         "vol +5",
+	"-":         "vol -5",
+	"_":         "vol -5",
+	"tab":       "genre next",
+	"shift+tab": "genre prev",
+	"ctrl+g":    "genres",
+	"f":         "fav",
+	"F":         "favorites",
+	"0":         "reset",
+	"1":         "preset 1",
+	"2":         "preset 2",
+	"3":         "preset 3",
+	"4":         "preset 4",
+	"5":         "preset 5",
+	"6":         "preset 6",
+	"7":         "preset 7",
+	"8":         "preset 8",
+	"9":         "preset 9",
+	"S":         "sort",
+	"Q":         "quality",
+	"b":         "previous",
+	"r":         "recent",
+	"t":         "tracks",
+	"i":         "info",
+	"m":         "mini",
+	"v":         "viz",
+	"e":         "eq",
+	"d":         "device",
+	"c":         "onair",
+	"o":         "open",
+	"y":         "copy",
+	"Y":         "copy link",
+}
+
+func init() {
+	actions = []action{
+		{name: "play", args: "[станция]", help: "Играть станцию (по ID, префиксу или названию)", run: cmdPlay, complete: completeStations},
+		{name: "toggle", help: "Играть станцию под курсором или остановить", run: cmdToggle},
+		{name: "stop", help: "Остановить", run: func(m *Model, _ []string) tea.Cmd { m.stopPlayback(); return nil }},
+		{name: "vol", args: "<0-100|+N|-N>", help: "Громкость", run: cmdVolume},
+		{name: "quality", args: "[320|128|64|hls]", help: "Качество потока", run: cmdQuality, complete: completeList(api.Qualities...)},
+		{name: "previous", help: "Предыдущая станция", run: func(m *Model, _ []string) tea.Cmd { return m.playPrevious() }},
+		{name: "recent", help: "Недавние станции", run: cmdRecent},
+		{name: "preset", args: "<1-9>", help: "Играть избранное по номеру", run: cmdPreset},
+		{name: "sleep", args: "<30m|1h|off>", help: "Остановить через заданное время", run: cmdSleep, complete: completeList("15m", "30m", "1h", "off")},
+		{name: "crossfade", args: "[секунды|off]", help: "Плавный переход между станциями", run: cmdCrossfade, complete: completeList("off", "1", "2", "3", "5")},
+
+		{name: "genre", args: "[next|prev|all|жанр,жанр|жанр+жанр]", help: "Фильтр по жанрам (, — ИЛИ, + — И)", run: cmdGenre, complete: completeGenres},
+		{name: "genres", help: "Выбор нескольких жанров", run: cmdGenres},
+		{name: "favorites", help: "Только избранное", run: cmdFavorites},
+		{name: "reset", help: "Сбросить фильтры", run: cmdReset},
+		{name: "sort", args: "[api|title|genre|recent|plays]", help: "Сортировка списка", run: cmdSort, complete: completeSort},
+		{name: "fav", args: "[export|import <файл>]", help: "Избранное: переключить, экспорт, импорт", run: cmdFav, complete: completeList("export", "import")},
+
+		{name: "search", help: "Поиск по станциям", run: cmdSearch},
+		{name: "next", help: "Следующее совпадение", run: func(m *Model, _ []string) tea.Cmd { m.nextMatch(); return nil }},
+		{name: "prev", help: "Предыдущее совпадение", run: func(m *Model, _ []string) tea.Cmd { m.prevMatch(); return nil }},
+		{name: "clear", help: "Сбросить поиск", run: func(m *Model, _ []string) tea.Cmd { m.clearSearch(); return nil }},
+		{name: "tracks", help: "Поиск по трекам в эфире", run: cmdTracks},
+		{name: "info", args: "[станция]", help: "Карточка станции: потоки, история, статистика", run: cmdInfo, complete: completeStations},
+		{name: "onair", help: "Колонка «в эфире»", run: cmdOnAir},
+
+		{name: "up", help: "Вверх", run: func(m *Model, _ []string) tea.Cmd { m.moveCursor(-1); return nil }},
+		{name: "down", help: "Вниз", run: func(m *Model, _ []string) tea.Cmd { m.moveCursor(1); return nil }},
+		{name: "top", help: "В начало списка", run: func(m *Model, _ []string) tea.Cmd { m.cursor = 0; return nil }},
+		{name: "bottom", help: "В конец списка", run: cmdBottom},
+
+		{name: "open", args: "[1-9]", help: "Открыть ссылку на трек", run: cmdOpen},
+		{name: "copy", args: "[track|link [1-9]]", help: "Копировать трек или ссылку", run: cmdCopy, complete: completeList("track", "link")},
+
+		{name: "mini", help: "Мини-плеер в одну строку", run: func(m *Model, _ []string) tea.Cmd { m.mini = !m.mini; return nil }},
+		{name: "viz", help: "Визуализатор спектра", run: cmdViz},
+		{name: "eq", args: "[пресет|station <пресет|off>]", help: "Эквалайзер и аудиофильтры", run: cmdEqualizer, complete: completeEqualizer},
+		{name: "device", args: "[устройство|auto]", help: "Аудиоустройство вывода", run: cmdDevice, complete: completeDevices},
+		{name: "palette", help: "Командная строка", run: func(m *Model, _ []string) tea.Cmd { m.openPalette(); return nil }},
+		{name: "help", help: "Справка", run: func(m *Model, _ []string) tea.Cmd { m.mode = modeHelp; return nil }},
+		{name: "quit", help: "Выход", run: cmdQuit},
+	}
+}
+
+// CommandInfo описывает команду для справки командной строки
+type CommandInfo struct {
+	Name string
+	Args string
+	Help string
+}
+
+// Commands возвращает все команды палитры
+func Commands() []CommandInfo {
+	list := make([]CommandInfo, len(actions))
+	for i, a := range actions {
+		list[i] = CommandInfo{Name: a.name, Args: a.args, Help: a.help}
+	}
+	return list
+}
+
+// IsCommand проверяет, что строка начинается с известной команды
+func IsCommand(line string) bool {
+	fields := strings.Fields(line)
+	return len(fields) > 0 && findAction(fields[0]) != nil
+}
+
+func findAction(name string) *action {
+	for i := range actions {
+		if actions[i].name == name {
+			return &actions[i]
+		}
+	}
+	return nil
+}
+
+// SetStartup задаёт команду, которая выполнится после загрузки станций
+func (m *Model) SetStartup(line string) {
+	m.startup = line
+}
+
+// execute выполняет строку команды: имя и аргументы через пробел
+func (m *Model) execute(line string) tea.Cmd {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+	a := findAction(fields[0])
+	if a == nil {
+		msg := "Неизвестная команда: " + fields[0]
+		if names := m.suggestCommands(fields[0]); len(names) > 0 {
+			msg += " — может, " + strings.Join(names, ", ") + "?"
+		}
+		return m.flash(msg)
+	}
+	return a.run(m, fields[1:])
+}
+
+func (m *Model) moveCursor(delta int) {
+	m.cursor += delta
+	if m.cursor > len(m.visibleList)-1 {
+		m.cursor = len(m.visibleList) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// findStation ищет станцию по ID, префиксу или названию, затем нечётким
+// поиском. Возвращает индекс станции или -1.
+func (m *Model) findStation(query string) int {
+	if id, err := strconv.Atoi(query); err == nil {
+		if idx := m.stationIndex(id); idx >= 0 {
+			return idx
+		}
+	}
+	for i, s := range m.stations {
+		if strings.EqualFold(s.Prefix, query) || strings.EqualFold(s.Title, query) {
+			return i
+		}
+	}
+
+	var results []searchMatch
+	for i, s := range m.stations {
+		genres := make([]string, len(s.Genres))
+		for j, g := range s.Genres {
+			genres[j] = g.Name
+		}
+		if match, ok := matchStation(query, s.Title, s.Tooltip, s.Prefix, genres); ok {
+			match.station = i
+			results = append(results, match)
+		}
+	}
+	if len(results) == 0 {
+		return -1
+	}
+	rankMatches(results)
+	return results[0].station
+}
+
+func cmdPlay(m *Model, args []string) tea.Cmd {
+	stationIdx := m.getStationAtCursor()
+	if len(args) > 0 {
+		query := strings.Join(args, " ")
+		stationIdx = m.findStation(query)
+		if stationIdx < 0 {
+			return m.flash("Станция не найдена: " + query)
+		}
+		m.jumpToStation(stationIdx)
+	}
+	if stationIdx < 0 || stationIdx == m.selected {
+		return nil
+	}
+	return m.playStation(stationIdx)
+}
+
+func cmdToggle(m *Model, _ []string) tea.Cmd {
+	stationIdx := m.getStationAtCursor()
+	if stationIdx < 0 {
+		return nil
+	}
+	// Если станция уже играет — останавливаем
+	if stationIdx == m.selected {
+		m.stopPlayback()
+		return nil
+	}
+	return m.playStation(stationIdx)
+}
+
+func cmdVolume(m *Model, args []string) tea.Cmd {
+	if len(args) == 0 {
+		return m.flash(fmt.Sprintf("Громкость: %d%%", m.player.Volume()))
+	}
+	arg := strings.TrimSuffix(args[0], "%")
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		return m.flash("Громкость — число от 0 до 100: " + args[0])
+	}
+	if arg[0] == '+' || arg[0] == '-' {
+		n += m.player.Volume()
+	}
+	m.player.SetVolume(n)
+	m.publishVolume()
+	return nil
+}
+
+func cmdQuality(m *Model, args []string) tea.Cmd {
+	if len(args) == 0 {
+		return m.cycleQuality()
+	}
+	q := strings.TrimSuffix(strings.ToLower(args[0]), "k")
+	for _, known := range api.Qualities {
+		if q == known {
+			return m.setQuality(q)
+		}
+	}
+	return m.flash("Неизвестное качество: " + args[0])
+}
+
+func cmdRecent(m *Model, _ []string) tea.Cmd {
+	m.mode = modeRecent
+	m.recentCursor = 0
+	// Курсор сразу на предыдущей станции, как в Alt+Tab
+	if recent := m.recentStations(); len(recent) > 1 && recent[0] == m.selected {
+		m.recentCursor = 1
+	}
+	return nil
+}
+
+func cmdPreset(m *Model, args []string) tea.Cmd {
+	if len(args) == 0 {
+		return nil
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 || n > len(m.config.Favorites) {
+		return nil
+	}
+	if idx := m.stationIndex(m.config.Favorites[n-1]); idx >= 0 {
+		return m.playStation(idx)
+	}
+	return nil
+}
+
+func cmdGenre(m *Model, args []string) tea.Cmd {
+	if len(args) == 0 {
+		m.setGenre(-1)
+		return nil
+	}
+
+	arg := strings.Join(args, " ")
+	switch strings.ToLower(arg) {
+	case "next":
+		genre := m.currentGenre + 1
+		if genre >= len(m.allGenres) {
+			genre = -1
+		}
+		m.setGenre(genre)
+		return nil
+	case "prev":
+		genre := m.currentGenre - 1
+		if genre < -1 {
+			genre = len(m.allGenres) - 1
+		}
+		m.setGenre(genre)
+		return nil
+	case "all", "все":
+		m.setGenre(-1)
+		return nil
+	}
+
+	sep, matchAll := ",", false
+	if strings.Contains(arg, "+") {
+		sep, matchAll = "+", true
+	}
+
+	var names []string
+	for _, part := range strings.Split(arg, sep) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		idx := m.findGenre(part)
+		if idx < 0 {
+			return m.flash("Жанр не найден: " + part)
+		}
+		names = append(names, m.allGenres[idx])
+	}
+
+	if len(names) == 1 {
+		m.setGenre(m.genreIndex(names[0]))
+		return nil
+	}
+	m.currentGenre = -1
+	m.genreFilter = names
+	m.genreAll = matchAll
+	m.saveGenres()
+	m.updateVisibleList()
+	m.clearSearch()
+	return nil
+}
+
+// findGenre ищет жанр без учёта регистра, затем по началу названия
+func (m *Model) findGenre(name string) int {
+	for i, g := range m.allGenres {
+		if strings.EqualFold(g, name) {
+			return i
+		}
+	}
+	for i, g := range m.allGenres {
+		if strings.HasPrefix(strings.ToLower(g), strings.ToLower(name)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func cmdGenres(m *Model, _ []string) tea.Cmd {
+	if len(m.allGenres) > 0 {
+		m.openGenrePicker()
+	}
+	return nil
+}
+
+func cmdFavorites(m *Model, _ []string) tea.Cmd {
+	m.showFavorites = !m.showFavorites
+	m.updateVisibleList()
+	m.clearSearch()
+	return nil
+}
+
+func cmdReset(m *Model, _ []string) tea.Cmd {
+	m.showFavorites = false
+	m.setGenre(-1)
+	return nil
+}
+
+func cmdSort(m *Model, args []string) tea.Cmd {
+	if len(args) == 0 {
+		m.cycleSort()
+		return m.flash("Сортировка: " + m.sort.String())
+	}
+	for i, mode := range sortModes {
+		if mode.key == args[0] {
+			m.setSort(sortMode(i))
+			return m.flash("Сортировка: " + m.sort.String())
+		}
+	}
+	return m.flash("Неизвестная сортировка: " + args[0])
+}
+
+func cmdFav(m *Model, args []string) tea.Cmd {
+	if len(args) == 0 {
+		if stationIdx := m.getStationAtCursor(); stationIdx >= 0 {
+			m.toggleFavorite(m.stations[stationIdx].ID)
+		}
+		return nil
+	}
+
+	if len(args) < 2 || (args[0] != "export" && args[0] != "import") {
+		return m.flash("Использование: fav export|import <файл>")
+	}
+	path := config.ExpandHome(args[1])
+	format := config.FormatFromPath(path)
+
+	if args[0] == "export" {
+		f, err := os.Create(path)
+		if err != nil {
+			return m.flash("Ошибка экспорта: " + err.Error())
+		}
+		err = m.config.ExportFavorites(f, format, m.stations)
+		// Ошибка записи может всплыть только при закрытии
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return m.flash("Ошибка экспорта: " + err.Error())
+		}
+		return m.flash(fmt.Sprintf("Экспортировано: %d → %s", len(m.config.Favorites), args[1]))
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return m.flash("Ошибка импорта: " + err.Error())
+	}
+	defer f.Close()
+	added, missing, err := m.config.ImportFavorites(f, format, m.stations)
+	if err != nil {
+		return m.flash("Ошибка импорта: " + err.Error())
+	}
+	m.config.Save()
+	m.updateVisibleList()
+	m.publishFavorites()
+	return m.flash(fmt.Sprintf("Добавлено в избранное: %d, не найдено: %d", added, len(missing)))
+}
+
+func cmdSearch(m *Model, _ []string) tea.Cmd {
+	m.mode = modeSearch
+	m.searchQuery = ""
+	return nil
+}
+
+func cmdTracks(m *Model, _ []string) tea.Cmd {
+	m.mode = modeTracks
+	m.trackQuery = ""
+	m.trackCursor = 0
+	m.onAirLoading = true
+	return fetchOnAir(m.onAirCache, m.stationIDs())
+}
+
+func cmdOnAir(m *Model, _ []string) tea.Cmd {
+	m.config.ShowOnAir = !m.config.ShowOnAir
+	m.config.Save()
+	if m.config.ShowOnAir {
+		return m.refreshOnAir()
+	}
+	return nil
+}
+
+func cmdBottom(m *Model, _ []string) tea.Cmd {
+	if len(m.visibleList) > 0 {
+		m.cursor = len(m.visibleList) - 1
+	}
+	return nil
+}
+
+// linkArg разбирает номер ссылки 1-9
+func (m *Model) linkArg(arg string) (trackLink, bool) {
+	n, err := strconv.Atoi(arg)
+	links := m.currentLinks()
+	if err != nil || n < 1 || n > len(links) {
+		return trackLink{}, false
+	}
+	return links[n-1], true
+}
+
+func cmdOpen(m *Model, args []string) tea.Cmd {
+	if len(args) == 0 {
+		// Номер ссылки придёт следующей клавишей
+		if len(m.currentLinks()) > 0 {
+			m.pendingKey = "o"
+		}
+		return nil
+	}
+	if link, ok := m.linkArg(args[0]); ok {
+		return m.openLink(link)
+	}
+	return nil
+}
+
+func cmdCopy(m *Model, args []string) tea.Cmd {
+	if len(args) == 0 || args[0] == "track" {
+		if title := m.trackTitle(); title != "" {
+			return m.copyText(title)
+		}
+		return nil
+	}
+	if args[0] != "link" {
+		return m.flash("Использование: copy [track|link [1-9]]")
+	}
+	if len(args) == 1 {
+		if len(m.currentLinks()) > 0 {
+			m.pendingKey = "Y"
+		}
+		return nil
+	}
+	if link, ok := m.linkArg(args[1]); ok {
+		return m.copyText(link.url)
+	}
+	return nil
+}
+
+func cmdQuit(m *Model, _ []string) tea.Cmd {
+	m.SaveSession()
+	m.stopPlayback()
+	return tea.Quit
+}
+
+// sleepMsg останавливает воспроизведение по таймеру сна
+type sleepMsg struct {
+	seq int
+}
+
+func cmdSleep(m *Model, args []string) tea.Cmd {
+	if len(args) == 0 {
+		if m.sleepAt.IsZero() {
+			return m.flash("Таймер сна не задан")
+		}
+		return m.flash("Остановка через " + formatRemaining(time.Until(m.sleepAt)))
+	}
+
+	m.sleepSeq++
+	if args[0] == "off" {
+		m.sleepAt = time.Time{}
+		return m.flash("Таймер сна выключен")
+	}
+
+	d, err := time.ParseDuration(args[0])
+	if err != nil {
+		// Просто число — минуты
+		minutes, convErr := strconv.Atoi(args[0])
+		if convErr != nil {
+			return m.flash("Неверная длительность: " + args[0])
+		}
+		d = time.Duration(minutes) * time.Minute
+	}
+	if d <= 0 {
+		return m.flash("Неверная длительность: " + args[0])
+	}
+
+	m.sleepAt = time.Now().Add(d)
+	seq := m.sleepSeq
+	return tea.Batch(
+		m.flash("Остановка через "+formatRemaining(d)),
+		tea.Tick(d, func(time.Time) tea.Msg { return sleepMsg{seq: seq} }),
+	)
+}
+
+// maxCrossfade — дольше переход между станциями уже мешает
+const maxCrossfade = 10 * time.Second
+
+func cmdCrossfade(m *Model, args []string) tea.Cmd {
+	if len(args) == 0 {
+		if d := m.player.Crossfade(); d > 0 {
+			return m.flash("Переход между станциями: " + d.String())
+		}
+		return m.flash("Переход между станциями выключен")
+	}
+
+	var d time.Duration
+	if args[0] != "off" {
+		var err error
+		d, err = time.ParseDuration(args[0])
+		if err != nil {
+			// Просто число — секунды
+			seconds, convErr := strconv.ParseFloat(args[0], 64)
+			if convErr != nil {
+				return m.flash("Неверная длительность: " + args[0])
+			}
+			d = time.Duration(seconds * float64(time.Second))
+		}
+		if d < 0 || d > maxCrossfade {
+			return m.flash("Переход — от 0 до " + maxCrossfade.String())
+		}
+	}
+
+	m.player.SetCrossfade(d)
+	m.config.Crossfade = d.Seconds()
+	m.config.Save()
+	if d == 0 {
+		return m.flash("Переход между станциями выключен")
+	}
+	return m.flash("Переход между станциями: " + d.String())
+}
+
+// formatRemaining показывает оставшееся время: «1ч05м», «29м», «40с»
+func formatRemaining(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d >= time.Hour:
+		return fmt.Sprintf("%dч%02dм", int(d.Hours()), int(d.Minutes())%60)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dм", int((d + 59*time.Second).Minutes()))
+	default:
+		return fmt.Sprintf("%dс", int(d.Seconds()))
+	}
+}
+
+// completeList — варианты из фиксированного списка
+func completeList(values ...string) func(*Model, []string) []string {
+	return func(*Model, []string) []string { return values }
+}
+
+func completeStations(m *Model, _ []string) []string {
+	prefixes := make([]string, len(m.stations))
+	for i, s := range m.stations {
+		prefixes[i] = s.Prefix
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
+
+func completeGenres(m *Model, _ []string) []string {
+	values := []string{"next", "prev", "all"}
+	for _, g := range m.allGenres {
+		values = append(values, strings.ToLower(g))
+	}
+	return values
+}
+
+func completeSort(*Model, []string) []string {
+	keys := make([]string, len(sortModes))
+	for i, mode := range sortModes {
+		keys[i] = mode.key
+	}
+	return keys
+}
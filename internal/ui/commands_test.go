@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isalikov/radio-record-cli/internal/config"
+)
+
+func TestCommandVolume(t *testing.T) {
+	m := genreModel(&config.Config{Favorites: []int{}, AlbumArt: "off"})
+
+	for _, tc := range []struct {
+		line     string
+		expected int
+	}{
+		{"vol 40", 40},
+		{"vol +5", 45},
+		{"vol -50", 0},
+		{"vol 120%", 100},
+	} {
+		m.execute(tc.line)
+		if got := m.player.Volume(); got != tc.expected {
+			t.Errorf("%q: expected volume %d, got %d", tc.line, tc.expected, got)
+		}
+	}
+}
+
+func TestCommandCrossfade(t *testing.T) {
+	m := genreModel(&config.Config{Favorites: []int{}, AlbumArt: "off"})
+
+	for _, tc := range []struct {
+		line     string
+		expected time.Duration
+	}{
+		{"crossfade 3", 3 * time.Second},
+		{"crossfade 1.5", 1500 * time.Millisecond},
+		{"crossfade 500ms", 500 * time.Millisecond},
+		{"crossfade 60", 500 * time.Millisecond},
+		{"crossfade abc", 500 * time.Millisecond},
+		{"crossfade off", 0},
+	} {
+		m.execute(tc.line)
+		if got := m.player.Crossfade(); got != tc.expected {
+			t.Errorf("%q: expected crossfade %v, got %v", tc.line, tc.expected, got)
+		}
+		if got := m.config.Crossfade; got != tc.expected.Seconds() {
+			t.Errorf("%q: expected %g seconds in the config, got %g", tc.line, tc.expected.Seconds(), got)
+		}
+	}
+}
+
+func TestCommandGenre(t *testing.T) {
+	m := genreModel(&config.Config{Favorites: []int{}, AlbumArt: "off"})
+
+	m.execute("genre house")
+	if ids := visibleIDs(m); len(ids) != 2 || m.currentGenre != m.genreIndex("HOUSE") {
+		t.Errorf("Expected HOUSE tab, got genre %d and %v", m.currentGenre, ids)
+	}
+
+	m.execute("genre house+deep")
+	if ids := visibleIDs(m); len(ids) != 1 || ids[0] != 2 || !m.genreAll {
+		t.Errorf("Expected only station 2 for HOUSE and DEEP, got %v", ids)
+	}
+
+	m.execute("genre techno,lounge")
+	if ids := visibleIDs(m); len(ids) != 2 || m.genreAll {
+		t.Errorf("Expected stations 3 and 4 for TECHNO or LOUNGE, got %v", ids)
+	}
+
+	m.execute("genre all")
+	if m.genreFiltered() {
+		t.Errorf("Expected no genre filter, got %v", m.genreFilter)
+	}
+}
+
+func TestCommandPlay(t *testing.T) {
+	m := genreModel(&config.Config{Favorites: []int{}, AlbumArt: "off"})
+
+	m.execute("play techno")
+	if m.selected < 0 || m.stations[m.selected].ID != 3 {
+		t.Fatalf("Expected station 3 playing, got %d", m.selected)
+	}
+	if id := m.stations[m.getStationAtCursor()].ID; id != 3 {
+		t.Errorf("Expected cursor on station 3, got %d", id)
+	}
+
+	m.execute("play nothing-like-this")
+	if m.stations[m.selected].ID != 3 {
+		t.Errorf("Expected unknown station to keep playing 3")
+	}
+}
+
+func TestPalette(t *testing.T) {
+	cfg := &config.Config{Favorites: []int{}, AlbumArt: "off"}
+	m := genreModel(cfg)
+
+	m = press(m, ":", "v", "o", "tab")
+	if m.mode != modeCommand || m.palette != "vol " {
+		t.Fatalf("Expected completed \"vol \", got %q", m.palette)
+	}
+
+	m = press(m, "4", "0", "enter")
+	if m.mode != modeNormal || m.player.Volume() != 40 {
+		t.Errorf("Expected volume 40, got %d", m.player.Volume())
+	}
+	if len(cfg.History) != 1 || cfg.History[0] != "vol 40" {
+		t.Errorf("Expected history [vol 40], got %v", cfg.History)
+	}
+
+	m = press(m, ":", "up")
+	if m.palette != "vol 40" {
+		t.Errorf("Expected history entry, got %q", m.palette)
+	}
+
+	// Сокращение дополняется, только если команда одна
+	if got := m.resolveCommand("vo 20"); got != "vol 20" {
+		t.Errorf("Expected \"vol 20\", got %q", got)
+	}
+	for _, line := range []string{"q", "plya deep", "x", "громк 20"} {
+		if got := m.resolveCommand(line); got != line {
+			t.Errorf("Expected %q left as is, got %q", line, got)
+		}
+	}
+
+	// Неизвестная команда не выполняется, а подсказывает варианты
+	m = press(m, "esc", ":", "q", "enter")
+	if m.mode != modeNormal || !strings.Contains(m.notice, "Неизвестная команда: q") || !strings.Contains(m.notice, "quit") {
+		t.Errorf("Expected suggestions for q, got %q", m.notice)
+	}
+	m.execute("громк 20")
+	if m.player.Volume() != 40 || !strings.Contains(m.notice, "vol") {
+		t.Errorf("Expected volume untouched and vol suggested, got %d, %q", m.player.Volume(), m.notice)
+	}
+}
+
+func TestKeymapCommands(t *testing.T) {
+	if !IsCommand("play deep") || IsCommand("dance") {
+		t.Errorf("IsCommand does not match the registry")
+	}
+	for key, line := range keymap {
+		if !IsCommand(line) {
+			t.Errorf("Key %q is bound to unknown command %q", key, line)
+		}
+	}
+}
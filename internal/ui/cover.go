@@ -0,0 +1,61 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/isalikov/radio-record-cli/internal/api"
+	"github.com/isalikov/radio-record-cli/internal/ui/artwork"
+)
+
+// Размер обложки в ячейках терминала — по высоте совпадает с блоком
+// «сейчас играет» (4 строки + рамка)
+const (
+	coverCols = 12
+	coverRows = 6
+)
+
+// coverMsg приносит отрисованную обложку
+type coverMsg struct {
+	url   string
+	cover string
+}
+
+// coverImage выбирает обложку нужного размера: для полутоновых блоков
+// хватает маленькой, графическим протоколам нужна побольше
+func coverImage(track *api.Track, protocol artwork.Protocol) string {
+	if track == nil {
+		return ""
+	}
+	if protocol != artwork.HalfBlock && track.Image200 != "" {
+		return track.Image200
+	}
+	return track.Image100
+}
+
+func loadCover(cache *artwork.Cache, renderer artwork.Renderer, url string) tea.Cmd {
+	return func() tea.Msg {
+		img, err := cache.Load(url)
+		if err != nil {
+			return coverMsg{url: url}
+		}
+		return coverMsg{url: url, cover: renderer.Render(img, coverCols, coverRows)}
+	}
+}
+
+// updateCover запускает загрузку обложки, если трек сменился
+func (m *Model) updateCover() tea.Cmd {
+	if m.art.Protocol == artwork.None {
+		return nil
+	}
+
+	url := coverImage(m.nowPlaying, m.art.Protocol)
+	if url == m.coverURL {
+		return nil
+	}
+
+	m.coverURL = url
+	m.cover = ""
+	if url == "" {
+		return nil
+	}
+	return loadCover(m.covers, m.art, url)
+}
@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/isalikov/radio-record-cli/internal/api"
+)
+
+// detailHistory — сколько последних треков показывать в карточке станции
+const detailHistory = 10
+
+// historyMsg приносит последние треки станции для карточки
+type historyMsg struct {
+	stationID int
+	tracks    []api.Track
+	err       error
+}
+
+func fetchHistory(client *api.Client, stationID int) tea.Cmd {
+	return func() tea.Msg {
+		tracks, err := client.GetHistory(stationID)
+		return historyMsg{stationID: stationID, tracks: tracks, err: err}
+	}
+}
+
+// openDetail открывает карточку станции и запрашивает историю треков
+func (m *Model) openDetail(stationIdx int) tea.Cmd {
+	m.mode = modeDetail
+	m.detailStation = stationIdx
+	m.detailTracks = nil
+	m.detailErr = nil
+	m.detailLoading = true
+	return fetchHistory(m.client, m.stations[stationIdx].ID)
+}
+
+func cmdInfo(m *Model, args []string) tea.Cmd {
+	stationIdx := m.getStationAtCursor()
+	if len(args) > 0 {
+		query := strings.Join(args, " ")
+		stationIdx = m.findStation(query)
+		if stationIdx < 0 {
+			return m.flash("Станция не найдена: " + query)
+		}
+	}
+	if stationIdx < 0 {
+		return nil
+	}
+	return m.openDetail(stationIdx)
+}
+
+// streamQualities — потоки станции с подписями в порядке клавиш 1-4
+func streamQualities(s api.Station) []struct{ label, url string } {
+	return []struct{ label, url string }{
+		{"320 kbps", s.Stream320},
+		{"128 kbps", s.Stream128},
+		{"64 kbps", s.Stream64},
+		{"HLS", s.StreamHLS},
+	}
+}
+
+func (m Model) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	station := m.stations[m.detailStation]
+
+	switch key := msg.String(); key {
+	case "esc", "i", "q":
+		m.mode = modeNormal
+
+	case "y":
+		return m, m.copyText(station.StreamURL(m.quality()))
+
+	case "1", "2", "3", "4":
+		if stream := streamQualities(station)[key[0]-'1']; stream.url != "" {
+			return m, m.copyText(stream.url)
+		}
+
+	case "enter", " ":
+		m.mode = modeNormal
+		m.jumpToStation(m.detailStation)
+		if m.detailStation != m.selected {
+			return m, m.playStation(m.detailStation)
+		}
+	}
+	return m, nil
+}
+
+// formatListening показывает время прослушивания: «3ч 25м», «12м», «40с»
+func formatListening(seconds float64) string {
+	d := time.Duration(seconds) * time.Second
+	switch {
+	case d >= time.Hour:
+		return fmt.Sprintf("%dч %dм", int(d.Hours()), int(d.Minutes())%60)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dм", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dс", int(d.Seconds()))
+	}
+}
+
+func (m Model) renderDetail() string {
+	station := m.stations[m.detailStation]
+	label := func(name string) string {
+		return dimStyle.Render(fmt.Sprintf("  %-13s", name))
+	}
+
+	var lines []string
+
+	title := "📻 " + truncateWidth(station.Title, m.width-5)
+	if m.config.IsFavorite(station.ID) {
+		title += " " + favoriteStyle.Render("♥")
+	}
+	lines = append(lines, titleStyle.Render(title))
+	lines = append(lines, strings.Repeat("─", m.width))
+
+	// Описание целиком: в списке оно обрезается
+	if station.Tooltip != "" {
+		tooltip := lipgloss.NewStyle().Width(m.width - 4).Render(station.Tooltip)
+		for _, line := range strings.Split(tooltip, "\n") {
+			lines = append(lines, "  "+line)
+		}
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, label("ID")+fmt.Sprint(station.ID))
+	lines = append(lines, label("Префикс")+station.Prefix)
+
+	genres := make([]string, len(station.Genres))
+	for i, g := range station.Genres {
+		genres[i] = g.Name
+	}
+	lines = append(lines, label("Жанры")+genreStyle.Render(truncateWidth(strings.Join(genres, ", "), m.width-15)))
+	if station.IconFill != "" {
+		lines = append(lines, label("Иконка")+station.IconFill)
+	}
+	lines = append(lines, "")
+
+	for i, stream := range streamQualities(station) {
+		// Ссылки длинные: обрезаем за подписью в 15 колонок
+		url := truncateWidth(stream.url, m.width-15)
+		if url == "" {
+			url = dimStyle.Render("—")
+		}
+		lines = append(lines, label(fmt.Sprintf("[%d] %s", i+1, stream.label))+url)
+	}
+	lines = append(lines, "")
+
+	st := m.stats.Get(station.ID)
+	if st.Plays > 0 {
+		lines = append(lines, label("Запусков")+fmt.Sprint(st.Plays))
+		lines = append(lines, label("Слушали")+formatListening(st.Seconds))
+		lines = append(lines, label("Последний")+st.LastPlayed.Local().Format("02.01.2006 15:04"))
+	} else {
+		lines = append(lines, label("Статистика")+dimStyle.Render("ещё не слушали"))
+	}
+	lines = append(lines, "")
+
+	lines = append(lines, dimStyle.Render("  Последние треки"))
+	switch {
+	case m.detailLoading:
+		lines = append(lines, dimStyle.Render("  ⏳ Загрузка..."))
+	case m.detailErr != nil:
+		lines = append(lines, dimStyle.Render("  Ошибка: "+m.detailErr.Error()))
+	case len(m.detailTracks) == 0:
+		lines = append(lines, dimStyle.Render("  Нет данных"))
+	}
+	// Треков столько, сколько помещается на экран
+	limit := m.height - len(lines) - 2
+	if limit > detailHistory {
+		limit = detailHistory
+	}
+	for i, track := range m.detailTracks {
+		if i >= limit {
+			break
+		}
+		line := fmt.Sprintf("  %-10s %s", track.TimeFormatted, trackLabel(&track))
+		lines = append(lines, normalStyle.Render(truncateWidth(line, m.width)))
+	}
+
+	// Внизу остаётся подсказка: на низком экране карточка обрезается
+	for len(lines) < m.height-2 {
+		lines = append(lines, "")
+	}
+	if len(lines) > m.height-2 && m.height > 2 {
+		lines = lines[:m.height-2]
+	}
+
+	lines = append(lines, strings.Repeat("─", m.width))
+	lines = append(lines, helpStyle.Render(truncateWidth(" y копировать поток │ 1-4 копировать качество │ Enter ▶ │ Esc назад", m.width)))
+
+	return strings.Join(lines, "\n")
+}
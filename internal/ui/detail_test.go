@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/isalikov/radio-record-cli/internal/api"
+	"github.com/isalikov/radio-record-cli/internal/config"
+)
+
+func TestDetailView(t *testing.T) {
+	m := genreModel(&config.Config{Favorites: []int{}, AlbumArt: "off"})
+	m.stations[0].Tooltip = strings.Repeat("Длинное описание станции. ", 8)
+	m.stations[0].Stream128 = "https://example.com/house_128"
+
+	m = press(m, "i")
+	if m.mode != modeDetail || m.detailStation != 0 || !m.detailLoading {
+		t.Fatalf("Expected detail view of the first station, got mode %d", m.mode)
+	}
+
+	// Ответ по другой станции не должен попасть в карточку
+	next, _ := m.Update(historyMsg{stationID: 2, tracks: []api.Track{{Artist: "Wrong"}}})
+	next, _ = next.Update(historyMsg{stationID: 1, tracks: []api.Track{
+		{Artist: "First", Song: "Track", TimeFormatted: "12:00"},
+	}})
+	m = next.(Model)
+
+	view := m.View()
+	for _, expected := range []string{"https://example.com/house_128", "First — Track", "ещё не слушали"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected %q in the detail view", expected)
+		}
+	}
+	if strings.Contains(view, "Wrong") {
+		t.Errorf("Expected history of another station to be ignored")
+	}
+	// Описание не обрезается: все слова на месте
+	if got := strings.Count(view, "Длинное"); got != 8 {
+		t.Errorf("Expected the full tooltip, got %d of 8 sentences", got)
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if next.(Model).mode != modeNormal {
+		t.Errorf("Expected Esc to close the detail view")
+	}
+}
+
+func TestDetailViewFitsSmallTerminal(t *testing.T) {
+	m := openOverlay(layoutModel(50, 14, true), modeDetail)
+
+	lines := strings.Split(m.renderDetail(), "\n")
+	if len(lines) != 14 {
+		t.Fatalf("Expected 14 lines, got %d", len(lines))
+	}
+	// Заголовок не уезжает за верх экрана, подсказка остаётся внизу
+	if !strings.Contains(lines[0], "Русский микс") || !strings.Contains(lines[13], "y копировать") {
+		t.Errorf("Expected the title on top and the hint at the bottom:\n%s", strings.Join(lines, "\n"))
+	}
+	for i, line := range lines {
+		if w := lipgloss.Width(line); w > 50 {
+			t.Errorf("Line %d is %d columns wide: %q", i, w, line)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/isalikov/radio-record-cli/internal/player"
+)
+
+// devicesMsg приносит список аудиоустройств mpv
+type devicesMsg struct {
+	devices []player.AudioDevice
+	err     error
+}
+
+// deviceCheckMsg приносит результат проверки устройства, набранного
+// в командной строке
+type deviceCheckMsg struct {
+	name string
+	err  error
+}
+
+func fetchDevices(p *player.Player) tea.Cmd {
+	return func() tea.Msg {
+		devices, err := p.AudioDevices()
+		return devicesMsg{devices: devices, err: err}
+	}
+}
+
+// openDevices открывает выбор устройства и запрашивает список у mpv
+func (m *Model) openDevices() tea.Cmd {
+	m.mode = modeDevices
+	m.devicesLoading = true
+	m.devicesErr = nil
+	return fetchDevices(m.player)
+}
+
+// currentDevice — выбранное устройство, auto, если mpv выбирает сам
+func (m *Model) currentDevice() string {
+	if name := m.player.AudioDevice(); name != "" {
+		return name
+	}
+	return player.AutoAudioDevice
+}
+
+// setDevice переключает вывод на лету и запоминает его в конфиге
+func (m *Model) setDevice(name string) tea.Cmd {
+	if err := m.player.SetAudioDevice(name); err != nil {
+		m.state.Fail(err)
+		return m.flash("Не удалось переключить устройство: " + err.Error())
+	}
+	m.config.AudioDevice = m.player.AudioDevice()
+	m.config.Save()
+	return m.flash("Устройство: " + m.currentDevice())
+}
+
+func cmdDevice(m *Model, args []string) tea.Cmd {
+	if len(args) == 0 {
+		return m.openDevices()
+	}
+	name := strings.Join(args, " ")
+	if name == player.AutoAudioDevice {
+		return m.setDevice(name)
+	}
+
+	// Набранное имя сверяем со списком mpv: опечатка не должна оставить
+	// без звука и попасть в конфиг
+	p := m.player
+	return func() tea.Msg {
+		return deviceCheckMsg{name: name, err: p.CheckAudioDevice(name)}
+	}
+}
+
+func completeDevices(m *Model, _ []string) []string {
+	names := []string{player.AutoAudioDevice}
+	for _, d := range m.devices {
+		if d.Name != player.AutoAudioDevice {
+			names = append(names, d.Name)
+		}
+	}
+	return names
+}
+
+// handleDevices ставит курсор на текущее устройство
+func (m *Model) handleDevices(msg devicesMsg) {
+	m.devicesLoading = false
+	m.devicesErr = msg.err
+	m.devices = msg.devices
+	m.deviceCursor = 0
+	for i, d := range m.devices {
+		if d.Name == m.currentDevice() {
+			m.deviceCursor = i
+		}
+	}
+}
+
+func (m Model) updateDevices(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "d", "q":
+		m.mode = modeNormal
+
+	case "up", "k":
+		if m.deviceCursor > 0 {
+			m.deviceCursor--
+		}
+
+	case "down", "j":
+		if m.deviceCursor < len(m.devices)-1 {
+			m.deviceCursor++
+		}
+
+	case "enter", " ":
+		if m.deviceCursor >= len(m.devices) {
+			return m, nil
+		}
+		m.mode = modeNormal
+		return m, m.setDevice(m.devices[m.deviceCursor].Name)
+	}
+	return m, nil
+}
+
+func (m Model) renderDevices() string {
+	var lines []string
+
+	lines = append(lines, titleStyle.Render("🔈 Аудиоустройство"))
+	lines = append(lines, strings.Repeat("─", m.width))
+
+	listHeight := m.height - 4
+	if listHeight < 3 {
+		listHeight = 3
+	}
+
+	switch {
+	case m.devicesLoading:
+		lines = append(lines, dimStyle.Render("  Загрузка..."))
+	case m.devicesErr != nil:
+		lines = append(lines, dimStyle.Render("  Не удалось получить список: "+m.devicesErr.Error()))
+	case len(m.devices) == 0:
+		lines = append(lines, dimStyle.Render("  mpv не нашёл устройств"))
+	}
+
+	// Прокручиваем так, чтобы курсор был виден
+	start := 0
+	if m.deviceCursor >= listHeight {
+		start = m.deviceCursor - listHeight + 1
+	}
+	for i := start; i < len(m.devices) && i < start+listHeight; i++ {
+		d := m.devices[i]
+
+		cursor := "  "
+		style := normalStyle
+		if i == m.deviceCursor {
+			cursor = "▸ "
+			style = selectedStyle
+		}
+		mark := "  "
+		if d.Name == m.currentDevice() {
+			mark = favoriteStyle.Render("✓ ")
+		}
+
+		// Имена устройств бывают длинными: обрезаем до оформления
+		text := truncateWidth(fmt.Sprintf("%s  %s", d.Description, d.Name), m.width-4)
+		lines = append(lines, style.Render(cursor)+mark+style.Render(text))
+	}
+
+	for len(lines) < listHeight+2 {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, strings.Repeat("─", m.width))
+	lines = append(lines, helpStyle.Render(truncateWidth(" ↑↓ выбор │ Enter переключить │ Esc назад", m.width)))
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/isalikov/radio-record-cli/internal/config"
+	"github.com/isalikov/radio-record-cli/internal/player"
+)
+
+func TestDevicePicker(t *testing.T) {
+	m := genreModel(&config.Config{Favorites: []int{}, AlbumArt: "off"})
+	m.player.SetAudioDevice("alsa/default")
+
+	m = press(m, "d")
+	if m.mode != modeDevices || !m.devicesLoading {
+		t.Fatalf("Expected device picker loading, got mode %d", m.mode)
+	}
+
+	next, _ := m.Update(devicesMsg{devices: []player.AudioDevice{
+		{Name: "auto", Description: "Autoselect device"},
+		{Name: "alsa/default", Description: "Default ALSA Output"},
+		{Name: "pulse/usb-headset", Description: "USB Headset"},
+	}})
+	m = next.(Model)
+	if m.deviceCursor != 1 {
+		t.Errorf("Expected cursor on the current device, got %d", m.deviceCursor)
+	}
+	if view := m.View(); !strings.Contains(view, "USB Headset") {
+		t.Errorf("Expected devices in the picker")
+	}
+
+	m = press(m, "j", "enter")
+	if m.mode != modeNormal || m.player.AudioDevice() != "pulse/usb-headset" {
+		t.Fatalf("Expected headset selected, got %q", m.player.AudioDevice())
+	}
+	if m.config.AudioDevice != "pulse/usb-headset" {
+		t.Errorf("Expected the device saved in the config, got %q", m.config.AudioDevice)
+	}
+
+	// auto — mpv выбирает сам, в конфиге пусто
+	m.execute("device auto")
+	if m.player.AudioDevice() != "" || m.config.AudioDevice != "" {
+		t.Errorf("Expected auto to clear the device")
+	}
+	if got := completeDevices(&m, nil); len(got) != 3 || got[0] != "auto" {
+		t.Errorf("Expected auto and two devices, got %v", got)
+	}
+
+	// Имя из командной строки переключается только после проверки
+	next, _ = m.Update(deviceCheckMsg{name: "pulse/typo", err: player.ErrUnknownDevice})
+	m = next.(Model)
+	if m.player.AudioDevice() != "" || m.config.AudioDevice != "" || !strings.Contains(m.notice, "pulse/typo") {
+		t.Errorf("Expected the unknown device rejected, got %q", m.player.AudioDevice())
+	}
+	next, _ = m.Update(deviceCheckMsg{name: "pulse/usb-headset"})
+	m = next.(Model)
+	if m.config.AudioDevice != "pulse/usb-headset" {
+		t.Errorf("Expected the checked device saved, got %q", m.config.AudioDevice)
+	}
+}
@@ -0,0 +1,290 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/isalikov/radio-record-cli/internal/config"
+	"github.com/isalikov/radio-record-cli/internal/player"
+)
+
+// applyEqualizer передаёт mpv фильтры пресета станции. Если станция уже
+// играет, фильтры меняются на лету.
+func (m *Model) applyEqualizer(stationID int) {
+	eq := m.config.EqualizerFor(stationID)
+	if err := m.player.SetAudioFilter(player.FilterChain(eq.Gains, eq.Normalize, eq.Mono)); err != nil {
+		m.state.Fail(err)
+	}
+}
+
+// eqStationID — станция, для которой открыт эквалайзер: играющая или под
+// курсором; 0, если станций нет
+func (m *Model) eqStationID() int {
+	idx := m.selected
+	if idx < 0 {
+		idx = m.getStationAtCursor()
+	}
+	if idx < 0 {
+		return 0
+	}
+	return m.stations[idx].ID
+}
+
+// reapplyEqualizer применяет изменения к играющей станции
+func (m *Model) reapplyEqualizer() {
+	m.config.Save()
+	if m.selected >= 0 {
+		m.applyEqualizer(m.stations[m.selected].ID)
+	}
+}
+
+// openEqualizer открывает окно с курсором на текущем пресете станции
+func (m *Model) openEqualizer() {
+	m.mode = modeEqualizer
+	m.eqCursor = 0
+	name := m.config.EqualizerName(m.eqStationID())
+	for i, eq := range m.config.EqualizerPresets() {
+		if eq.Name == name {
+			m.eqCursor = i
+		}
+	}
+}
+
+// adjustGain меняет полосу эквалайзера. Изменения сохраняются в пресет
+// custom, который становится текущим там же, где был выбран прежний:
+// для станции или для всех.
+func (m *Model) adjustGain(band int, delta float64) {
+	stationID := m.eqStationID()
+	eq := m.config.EqualizerFor(stationID)
+
+	gains := make([]float64, len(player.EQBands))
+	copy(gains, eq.Gains)
+	gains[band] = math.Max(-player.MaxGain, math.Min(player.MaxGain, gains[band]+delta))
+
+	eq.Name = config.CustomEqualizer
+	eq.Gains = gains
+	m.config.SaveEqualizer(eq)
+
+	if _, ok := m.config.StationEQ[stationID]; ok {
+		m.config.SetStationEqualizer(stationID, eq.Name)
+	} else {
+		m.config.Equalizer = eq.Name
+	}
+	m.reapplyEqualizer()
+	m.openEqualizer()
+}
+
+func cmdEqualizer(m *Model, args []string) tea.Cmd {
+	if len(args) == 0 {
+		m.openEqualizer()
+		return nil
+	}
+
+	// eq station <пресет|off> — только для текущей станции
+	station := args[0] == "station"
+	if station {
+		args = args[1:]
+		if len(args) == 0 || m.eqStationID() == 0 {
+			return m.flash("Использование: eq station <пресет|off>")
+		}
+	}
+
+	name := args[0]
+	if name != "off" && !m.hasEqualizer(name) {
+		return m.flash("Неизвестный пресет: " + name)
+	}
+
+	switch {
+	case station && name == "off":
+		m.config.SetStationEqualizer(m.eqStationID(), "")
+	case station:
+		m.config.SetStationEqualizer(m.eqStationID(), name)
+	case name == "off":
+		m.config.Equalizer = config.DefaultEqualizer
+	default:
+		m.config.Equalizer = name
+	}
+	m.reapplyEqualizer()
+	return m.flash("Эквалайзер: " + m.config.EqualizerName(m.eqStationID()))
+}
+
+func (m *Model) hasEqualizer(name string) bool {
+	for _, eq := range m.config.EqualizerPresets() {
+		if eq.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func completeEqualizer(m *Model, args []string) []string {
+	var names []string
+	if len(args) == 0 {
+		names = append(names, "station")
+	}
+	for _, eq := range m.config.EqualizerPresets() {
+		names = append(names, eq.Name)
+	}
+	return append(names, "off")
+}
+
+func (m Model) updateEqualizer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	presets := m.config.EqualizerPresets()
+	stationID := m.eqStationID()
+
+	switch msg.String() {
+	case "esc", "e", "q":
+		m.mode = modeNormal
+
+	case "up", "k":
+		if m.eqCursor > 0 {
+			m.eqCursor--
+		}
+
+	case "down", "j":
+		if m.eqCursor < len(presets)-1 {
+			m.eqCursor++
+		}
+
+	case "enter", " ":
+		// Для всех станций
+		m.config.Equalizer = presets[m.eqCursor].Name
+		m.reapplyEqualizer()
+
+	case "s":
+		if stationID != 0 {
+			m.config.SetStationEqualizer(stationID, presets[m.eqCursor].Name)
+			m.reapplyEqualizer()
+		}
+
+	case "x", "backspace":
+		if stationID != 0 {
+			m.config.SetStationEqualizer(stationID, "")
+			m.reapplyEqualizer()
+			m.openEqualizer()
+		}
+
+	case "left", "h":
+		if m.eqBand > 0 {
+			m.eqBand--
+		}
+
+	case "right", "l":
+		if m.eqBand < len(player.EQBands)-1 {
+			m.eqBand++
+		}
+
+	case "+", "=":
+		m.adjustGain(m.eqBand, 1)
+
+	case "-", "_":
+		m.adjustGain(m.eqBand, -1)
+
+	case "0":
+		eq := m.config.EqualizerFor(stationID)
+		if m.eqBand < len(eq.Gains) && eq.Gains[m.eqBand] != 0 {
+			m.adjustGain(m.eqBand, -eq.Gains[m.eqBand])
+		}
+	}
+	return m, nil
+}
+
+// bandLabel подписывает частоту полосы: «31», «1k», «16k»
+func bandLabel(freq int) string {
+	if freq >= 1000 {
+		return fmt.Sprintf("%dk", freq/1000)
+	}
+	return fmt.Sprint(freq)
+}
+
+// renderGainBar рисует усиление полосой от центра: влево — срез, вправо —
+// подъём
+func renderGainBar(gain float64) string {
+	cells := int(math.Round(math.Abs(gain)))
+	left := strings.Repeat(" ", player.MaxGain)
+	right := strings.Repeat(" ", player.MaxGain)
+	if gain < 0 {
+		left = strings.Repeat(" ", player.MaxGain-cells) + strings.Repeat("█", cells)
+	} else {
+		right = strings.Repeat("█", cells) + strings.Repeat(" ", player.MaxGain-cells)
+	}
+	return left + "│" + right
+}
+
+func (m Model) renderEqualizer() string {
+	var lines []string
+
+	stationID := m.eqStationID()
+	title := "🎚  Эквалайзер"
+	if idx := m.stationIndex(stationID); idx >= 0 {
+		title += " — " + m.stations[idx].Title
+	}
+	lines = append(lines, titleStyle.Render(title))
+
+	common := m.config.Equalizer
+	if common == "" {
+		common = config.DefaultEqualizer
+	}
+	own := "—"
+	if name, ok := m.config.StationEQ[stationID]; ok {
+		own = name
+	}
+	lines = append(lines, dimStyle.Render("  Для всех: ")+genreStyle.Render(common)+dimStyle.Render("   Для станции: ")+genreStyle.Render(own))
+	lines = append(lines, strings.Repeat("─", m.width))
+
+	active := m.config.EqualizerName(stationID)
+	for i, eq := range m.config.EqualizerPresets() {
+		cursor := "  "
+		style := normalStyle
+		if i == m.eqCursor {
+			cursor = "▸ "
+			style = selectedStyle
+		}
+		mark := "  "
+		if eq.Name == active {
+			mark = favoriteStyle.Render("✓ ")
+		}
+
+		var extra []string
+		if eq.Normalize != "" {
+			extra = append(extra, eq.Normalize)
+		}
+		if eq.Mono {
+			extra = append(extra, "моно")
+		}
+		line := fmt.Sprintf("%s%s%-14s %s", cursor, mark, eq.Name, dimStyle.Render(strings.Join(extra, ", ")))
+		lines = append(lines, style.Render(line))
+	}
+	lines = append(lines, "")
+
+	// Полосы текущего пресета станции
+	eq := m.config.EqualizerFor(stationID)
+	for i, freq := range player.EQBands {
+		gain := 0.0
+		if i < len(eq.Gains) {
+			gain = eq.Gains[i]
+		}
+		cursor := "  "
+		style := dimStyle
+		if i == m.eqBand {
+			cursor = "▸ "
+			style = selectedStyle
+		}
+		line := fmt.Sprintf("%s%4s Гц %s %+3.0f дБ", cursor, bandLabel(freq), renderGainBar(gain), gain)
+		lines = append(lines, style.Render(line))
+	}
+
+	for len(lines) < m.height-2 {
+		lines = append(lines, "")
+	}
+	if len(lines) > m.height-2 && m.height > 2 {
+		lines = lines[:m.height-2]
+	}
+
+	lines = append(lines, strings.Repeat("─", m.width))
+	lines = append(lines, helpStyle.Render(truncateWidth(" Enter для всех │ s для станции │ x сбросить станцию │ ←→ полоса │ +/- дБ │ Esc назад", m.width)))
+
+	return strings.Join(lines, "\n")
+}
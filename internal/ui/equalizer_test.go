@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/isalikov/radio-record-cli/internal/config"
+	"github.com/isalikov/radio-record-cli/internal/player"
+)
+
+func TestEqualizerPresets(t *testing.T) {
+	m := genreModel(&config.Config{Favorites: []int{}, AlbumArt: "off"})
+	m.selected = 0 // Как будто играет первая станция
+
+	m = press(m, "e")
+	if m.mode != modeEqualizer || m.eqCursor != 0 {
+		t.Fatalf("Expected equalizer on the flat preset, got mode %d, cursor %d", m.mode, m.eqCursor)
+	}
+
+	// bass-boost для всех станций, фильтр меняется сразу
+	m = press(m, "j", "enter")
+	if m.config.Equalizer != "bass-boost" {
+		t.Fatalf("Expected bass-boost for all stations, got %q", m.config.Equalizer)
+	}
+	bass := config.DefaultEqualizers[1]
+	if got, want := m.player.AudioFilter(), player.FilterChain(bass.Gains, bass.Normalize, bass.Mono); got != want {
+		t.Errorf("Expected filter %q, got %q", want, got)
+	}
+
+	// Свой пресет станции важнее общего
+	m = press(m, "j", "j", "j", "s")
+	if got := m.config.EqualizerName(1); got != "vocal" {
+		t.Errorf("Expected vocal for the station, got %q", got)
+	}
+	if got := m.config.EqualizerName(2); got != "bass-boost" {
+		t.Errorf("Expected bass-boost for other stations, got %q", got)
+	}
+
+	m = press(m, "x")
+	if _, ok := m.config.StationEQ[1]; ok || m.eqCursor != 1 {
+		t.Errorf("Expected the station override removed, cursor on bass-boost")
+	}
+
+	m = press(m, "esc")
+	if m.mode != modeNormal {
+		t.Errorf("Expected esc to close the equalizer")
+	}
+}
+
+func TestEqualizerBands(t *testing.T) {
+	m := genreModel(&config.Config{Favorites: []int{}, AlbumArt: "off"})
+	m.selected = 0
+	m.config.SetStationEqualizer(1, "bass-cut")
+
+	// Правка полосы создаёт пресет custom на основе текущего
+	m = press(m, "e", "l", "l", "+", "+")
+	eq := m.config.EqualizerFor(1)
+	if eq.Name != config.CustomEqualizer || m.config.StationEQ[1] != config.CustomEqualizer {
+		t.Fatalf("Expected custom preset for the station, got %q", eq.Name)
+	}
+	if m.config.Equalizer != "" {
+		t.Errorf("Expected the common preset untouched, got %q", m.config.Equalizer)
+	}
+	cut := config.DefaultEqualizers[2].Gains
+	if eq.Gains[0] != cut[0] || eq.Gains[2] != cut[2]+2 {
+		t.Errorf("Expected band 3 raised by 2 dB from bass-cut, got %v", eq.Gains)
+	}
+	if !strings.Contains(m.player.AudioFilter(), "equalizer=f=125") {
+		t.Errorf("Expected the custom filter applied, got %q", m.player.AudioFilter())
+	}
+
+	// Усиление ограничено
+	for i := 0; i < 20; i++ {
+		m = press(m, "-")
+	}
+	if got := m.config.EqualizerFor(1).Gains[2]; got != -player.MaxGain {
+		t.Errorf("Expected gain clamped to %d, got %g", -player.MaxGain, got)
+	}
+
+	view := m.View()
+	for _, expected := range []string{"Эквалайзер — House", "custom", "125 Гц"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected %q in the equalizer view", expected)
+		}
+	}
+}
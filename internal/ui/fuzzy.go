@@ -0,0 +1,249 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Веса для ранжирования нечёткого поиска
+const (
+	scoreMatch       = 16 // за каждый совпавший символ запроса
+	scoreConsecutive = 8  // бонус за символ сразу после предыдущего совпадения
+	scoreWordStart   = 10 // бонус за совпадение в начале слова
+	scoreTextStart   = 8  // бонус за совпадение с первого символа
+	scoreSubstring   = 24 // бонус за точное вхождение подстроки
+	penaltyGapStart  = 3
+	penaltyGapRune   = 1
+)
+
+// translit — варианты латинской записи кириллических букв.
+// Пустая строка означает, что букву можно пропустить (ъ, ь).
+var translit = map[rune][]string{
+	'а': {"a"}, 'б': {"b"}, 'в': {"v", "w"}, 'г': {"g"}, 'д': {"d"},
+	'е': {"e", "ye"}, 'ё': {"yo", "e"}, 'ж': {"zh", "j"}, 'з': {"z"},
+	'и': {"i"}, 'й': {"y", "j", "i"}, 'к': {"k", "c"}, 'л': {"l"},
+	'м': {"m"}, 'н': {"n"}, 'о': {"o"}, 'п': {"p"}, 'р': {"r"},
+	'с': {"s", "c"}, 'т': {"t"}, 'у': {"u"}, 'ф': {"f"},
+	'х': {"kh", "h"}, 'ц': {"ts", "c"}, 'ч': {"ch"}, 'ш': {"sh"},
+	'щ': {"sch", "sh"}, 'ъ': {""}, 'ы': {"y"}, 'ь': {""},
+	'э': {"e", "a"}, 'ю': {"yu", "u"}, 'я': {"ya", "a"},
+}
+
+// Раскладки клавиатуры: символы на одних и тех же клавишах
+const (
+	layoutLatin    = "`qwertyuiop[]asdfghjkl;'zxcvbnm,."
+	layoutCyrillic = "ёйцукенгшщзхъфывапролджэячсмитьбю"
+)
+
+var (
+	latinToCyrillic = make(map[rune]rune)
+	cyrillicToLatin = make(map[rune]rune)
+)
+
+func init() {
+	lat := []rune(layoutLatin)
+	cyr := []rune(layoutCyrillic)
+	for i := range lat {
+		latinToCyrillic[lat[i]] = cyr[i]
+		cyrillicToLatin[cyr[i]] = lat[i]
+	}
+}
+
+// switchLayout переводит текст, набранный не в той раскладке.
+// Возвращает false, если перевод ничего не изменил.
+func switchLayout(query string) (string, bool) {
+	var b strings.Builder
+	changed := false
+	for _, r := range query {
+		if c, ok := latinToCyrillic[r]; ok {
+			b.WriteRune(c)
+			changed = true
+		} else if c, ok := cyrillicToLatin[r]; ok {
+			b.WriteRune(c)
+			changed = true
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), changed
+}
+
+// queryVariants возвращает запрос в нижнем регистре и его вариант
+// в другой раскладке
+func queryVariants(query string) []string {
+	q := string(lowerRunes(query))
+	variants := []string{q}
+	if switched, ok := switchLayout(q); ok {
+		variants = append(variants, switched)
+	}
+	return variants
+}
+
+// lowerRunes переводит текст в нижний регистр руна в руну: позиции
+// совпадений должны указывать на руны исходного текста, поэтому текст
+// не должен менять длину при переводе.
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// fuzzyMatch ищет запрос в тексте как подпоследовательность с учётом
+// транслитерации. Возвращает оценку и позиции (в рунах) совпавших символов.
+// Запрос должен быть в нижнем регистре.
+func fuzzyMatch(query, text string) (int, []int, bool) {
+	if query == "" {
+		return 0, nil, false
+	}
+
+	q := []rune(query)
+	t := lowerRunes(text)
+
+	// Точное вхождение — лучший вариант, подсвечиваем его целиком
+	if idx := runeIndex(t, q); idx >= 0 {
+		positions := make([]int, len(q))
+		for i := range q {
+			positions[i] = idx + i
+		}
+		return scorePositions(t, positions, len(q)) + scoreSubstring, positions, true
+	}
+
+	var positions []int
+	qi := 0
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		r := t[ti]
+		if r == q[qi] {
+			positions = append(positions, ti)
+			qi++
+			continue
+		}
+		for _, form := range translit[r] {
+			if form == "" {
+				continue
+			}
+			f := []rune(form)
+			if hasRunePrefix(q[qi:], f) {
+				positions = append(positions, ti)
+				qi += len(f)
+				break
+			}
+		}
+	}
+
+	if qi < len(q) {
+		return 0, nil, false
+	}
+
+	score := scorePositions(t, positions, len(q))
+	if score < scoreMatch/2*len(q) {
+		return 0, nil, false
+	}
+	return score, positions, true
+}
+
+func scorePositions(text []rune, positions []int, queryLen int) int {
+	score := queryLen * scoreMatch
+	for i, pos := range positions {
+		if pos == 0 {
+			score += scoreTextStart
+		}
+		if pos == 0 || !unicode.IsLetter(text[pos-1]) && !unicode.IsDigit(text[pos-1]) {
+			score += scoreWordStart
+		}
+		if i == 0 {
+			continue
+		}
+		gap := pos - positions[i-1] - 1
+		if gap == 0 || gap > 0 && onlySkippable(text[positions[i-1]+1:pos]) {
+			score += scoreConsecutive
+		} else {
+			score -= penaltyGapStart + gap*penaltyGapRune
+		}
+	}
+	return score
+}
+
+// onlySkippable сообщает, состоит ли промежуток только из ъ/ь
+func onlySkippable(runes []rune) bool {
+	for _, r := range runes {
+		if r != 'ъ' && r != 'ь' {
+			return false
+		}
+	}
+	return true
+}
+
+func runeIndex(text, sub []rune) int {
+	for i := 0; i+len(sub) <= len(text); i++ {
+		if hasRunePrefix(text[i:], sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+func hasRunePrefix(s, prefix []rune) bool {
+	if len(prefix) > len(s) {
+		return false
+	}
+	for i := range prefix {
+		if s[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// searchMatch — результат поиска по станции
+type searchMatch struct {
+	station int
+	score   int
+	title   []int // позиции совпадений в названии
+	tooltip []int // позиции совпадений в описании
+}
+
+// matchStation сопоставляет запрос со всеми полями станции: название,
+// описание, жанры и префикс. Название весит больше остальных полей,
+// позиции совпадений сохраняются для названия и описания.
+func matchStation(query string, title, tooltip, prefix string, genres []string) (searchMatch, bool) {
+	var m searchMatch
+	titleScore, tooltipScore := 0, 0
+	found := false
+
+	for _, q := range queryVariants(query) {
+		if score, pos, ok := fuzzyMatch(q, title); ok && (m.title == nil || score+scoreMatch > titleScore) {
+			titleScore, m.title = score+scoreMatch, pos
+		}
+		if score, pos, ok := fuzzyMatch(q, tooltip); ok && (m.tooltip == nil || score > tooltipScore) {
+			tooltipScore, m.tooltip = score, pos
+		}
+		for _, field := range append([]string{prefix}, genres...) {
+			if score, _, ok := fuzzyMatch(q, field); ok && (!found || score-scoreMatch/2 > m.score) {
+				m.score = score - scoreMatch/2
+				found = true
+			}
+		}
+	}
+
+	if m.title != nil && (!found || titleScore > m.score) {
+		m.score = titleScore
+		found = true
+	}
+	if m.tooltip != nil && (!found || tooltipScore > m.score) {
+		m.score = tooltipScore
+		found = true
+	}
+
+	return m, found
+}
+
+// rankMatches сортирует результаты по убыванию оценки; при равенстве
+// сохраняется порядок списка
+func rankMatches(matches []searchMatch) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+}
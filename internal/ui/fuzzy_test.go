@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		query     string
+		text      string
+		ok        bool
+		positions []int
+	}{
+		{"deep", "Deep", true, []int{0, 1, 2, 3}},
+		{"дип", "Дип-хаус", true, []int{0, 1, 2}},
+		{"rap", "Рэп", true, []int{0, 1, 2}},
+		{"hip", "Хип-хоп", true, []int{0, 1, 2}},
+		{"chill", "Чилл-аут", true, []int{0, 1, 2, 3}},
+		{"rmx", "Remix", true, []int{0, 2, 4}},
+		{"xyz", "Record", false, nil},
+	}
+
+	for _, tt := range tests {
+		_, positions, ok := fuzzyMatch(tt.query, tt.text)
+		if ok != tt.ok {
+			t.Errorf("fuzzyMatch(%q, %q) ok = %v, expected %v", tt.query, tt.text, ok, tt.ok)
+			continue
+		}
+		if ok && !reflect.DeepEqual(positions, tt.positions) {
+			t.Errorf("fuzzyMatch(%q, %q) positions = %v, expected %v", tt.query, tt.text, positions, tt.positions)
+		}
+	}
+}
+
+func TestFuzzyMatchRanking(t *testing.T) {
+	exact, _, _ := fuzzyMatch("house", "House")
+	scattered, _, _ := fuzzyMatch("house", "Hot Ounce Used Seven")
+	if exact <= scattered {
+		t.Errorf("Expected exact match to rank higher: %d <= %d", exact, scattered)
+	}
+}
+
+func TestSwitchLayout(t *testing.T) {
+	tests := map[string]string{
+		"вууз": "deep",
+		"h'g":  "рэп",
+		"123":  "123",
+	}
+
+	for query, expected := range tests {
+		if got, _ := switchLayout(query); got != expected {
+			t.Errorf("switchLayout(%q) = %q, expected %q", query, got, expected)
+		}
+	}
+}
+
+func TestMatchStation(t *testing.T) {
+	// Запрос набран в русской раскладке
+	m, ok := matchStation("вууз", "Deep", "Глубокое house-звучание", "deep", nil)
+	if !ok || !reflect.DeepEqual(m.title, []int{0, 1, 2, 3}) {
+		t.Errorf("Expected layout-switched match in title, got %+v", m)
+	}
+
+	// Совпадение только по жанру
+	m, ok = matchStation("techno", "Record", "Танцевальный мейнстрим", "rr_main", []string{"TECHNO"})
+	if !ok || m.title != nil || m.tooltip != nil {
+		t.Errorf("Expected genre-only match, got %+v (ok=%v)", m, ok)
+	}
+
+	if _, ok := matchStation("zzz", "Record", "Танцевальный мейнстрим", "rr_main", nil); ok {
+		t.Error("Expected no match")
+	}
+}
+
+func TestFuzzyMatchPositionsOriginalRunes(t *testing.T) {
+	// Позиции указывают на руны исходного текста и после İ, у которой
+	// полная строчная форма — две руны
+	text := "İstanbul FM"
+	_, positions, ok := fuzzyMatch("fm", text)
+	if !ok {
+		t.Fatal("Expected a match")
+	}
+	runes := []rune(text)
+	if got := string([]rune{runes[positions[0]], runes[positions[1]]}); got != "FM" {
+		t.Errorf("Expected positions of FM in the original text, got %v (%q)", positions, got)
+	}
+
+	if _, _, ok := fuzzyMatch(queryVariants("İST")[0], text); !ok {
+		t.Errorf("Expected an upper-case İ in the query to match")
+	}
+}
+
+func TestHighlightMatchCyrillic(t *testing.T) {
+	// Transform работает при любом цветовом профиле: скобки показывают,
+	// какие руны подсвечены и что текст не испорчен при разрезании
+	style := lipgloss.NewStyle()
+	marker := style.Transform(func(s string) string { return "[" + s + "]" })
+	for _, tc := range []struct {
+		positions []int
+		expected  string
+	}{
+		{[]int{0, 1, 8}, "[Ру]сский [М]икс"},
+		{[]int{6, 7}, "Русски[й ]Микс"},
+		{[]int{11}, "Русский Мик[с]"},
+	} {
+		if got := highlightMatch("Русский Микс", tc.positions, marker); got != tc.expected {
+			t.Errorf("highlightMatch(%v) = %q, expected %q", tc.positions, got, tc.expected)
+		}
+	}
+
+	if got := highlightMatch("Record", nil, style); got != "Record" {
+		t.Errorf("Expected text without positions unchanged, got %q", got)
+	}
+}
@@ -0,0 +1,267 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/isalikov/radio-record-cli/internal/api"
+)
+
+// extractGenres строит список вкладок в порядке жанров из API. Жанры без
+// станций пропускаются, жанры станций, которых нет в списке API, идут
+// в конце по алфавиту.
+func (m *Model) extractGenres(apiGenres []api.Genre) {
+	used := make(map[string]bool)
+	for _, s := range m.stations {
+		for _, g := range s.Genres {
+			used[g.Name] = true
+		}
+	}
+
+	m.allGenres = []string{}
+	for _, g := range apiGenres {
+		if used[g.Name] {
+			m.allGenres = append(m.allGenres, g.Name)
+			delete(used, g.Name)
+		}
+	}
+
+	var rest []string
+	for name := range used {
+		rest = append(rest, name)
+	}
+	sort.Strings(rest)
+	m.allGenres = append(m.allGenres, rest...)
+}
+
+// hasGenres проверяет станцию по фильтру жанров: вкладке или набору
+// жанров из окна выбора
+func (m *Model) hasGenres(s api.Station) bool {
+	names := m.genreFilter
+	matchAll := m.genreAll
+	if len(names) == 0 {
+		if m.currentGenre < 0 || m.currentGenre >= len(m.allGenres) {
+			return true
+		}
+		names = []string{m.allGenres[m.currentGenre]}
+	}
+
+	found := 0
+	for _, name := range names {
+		for _, g := range s.Genres {
+			if g.Name == name {
+				found++
+				break
+			}
+		}
+	}
+	if matchAll {
+		return found == len(names)
+	}
+	return found > 0
+}
+
+// genreFiltered — выбран ли какой-нибудь жанр
+func (m *Model) genreFiltered() bool {
+	return m.currentGenre >= 0 || len(m.genreFilter) > 0
+}
+
+// setGenre переключает вкладку жанра и сбрасывает набор из окна выбора
+func (m *Model) setGenre(genre int) {
+	m.currentGenre = genre
+	m.genreFilter = nil
+	m.saveGenres()
+	m.updateVisibleList()
+	m.clearSearch()
+}
+
+// saveGenres запоминает фильтр жанров в конфиге
+func (m *Model) saveGenres() {
+	m.config.Genres.Names = nil
+	m.config.Genres.All = m.genreAll
+	if len(m.genreFilter) > 0 {
+		m.config.Genres.Names = append([]string{}, m.genreFilter...)
+	} else if m.currentGenre >= 0 && m.currentGenre < len(m.allGenres) {
+		m.config.Genres.Names = []string{m.allGenres[m.currentGenre]}
+	}
+	m.config.Save()
+}
+
+// restoreGenres восстанавливает фильтр из конфига после загрузки станций.
+// Жанры, которых больше нет, отбрасываются.
+func (m *Model) restoreGenres() {
+	m.genreAll = m.config.Genres.All
+	var names []string
+	for _, name := range m.config.Genres.Names {
+		if m.genreIndex(name) >= 0 {
+			names = append(names, name)
+		}
+	}
+
+	switch len(names) {
+	case 0:
+	case 1:
+		m.currentGenre = m.genreIndex(names[0])
+	default:
+		m.genreFilter = names
+	}
+}
+
+func (m *Model) genreIndex(name string) int {
+	for i, g := range m.allGenres {
+		if g == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// genreFilterLabel описывает набор жанров для строки статуса
+func (m *Model) genreFilterLabel() string {
+	sep := " или "
+	if m.genreAll {
+		sep = " и "
+	}
+	return strings.Join(m.genreFilter, sep)
+}
+
+// openGenrePicker открывает окно выбора с текущим фильтром
+func (m *Model) openGenrePicker() {
+	m.mode = modeGenres
+	m.pickerCursor = 0
+	m.pickerAll = m.genreAll
+	m.pickerSelected = map[string]bool{}
+	for _, name := range m.genreFilter {
+		m.pickerSelected[name] = true
+	}
+	if len(m.genreFilter) == 0 && m.currentGenre >= 0 {
+		m.pickerSelected[m.allGenres[m.currentGenre]] = true
+		m.pickerCursor = m.currentGenre
+	}
+}
+
+func (m Model) updateGenrePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+g":
+		m.mode = modeNormal
+
+	case "up", "k":
+		if m.pickerCursor > 0 {
+			m.pickerCursor--
+		}
+
+	case "down", "j":
+		if m.pickerCursor < len(m.allGenres)-1 {
+			m.pickerCursor++
+		}
+
+	case " ", "x":
+		if m.pickerCursor < len(m.allGenres) {
+			name := m.allGenres[m.pickerCursor]
+			m.pickerSelected[name] = !m.pickerSelected[name]
+		}
+
+	case "a":
+		m.pickerAll = !m.pickerAll
+
+	case "0", "backspace":
+		m.pickerSelected = map[string]bool{}
+
+	case "enter":
+		// Порядок жанров в фильтре — порядок вкладок
+		var names []string
+		for _, name := range m.allGenres {
+			if m.pickerSelected[name] {
+				names = append(names, name)
+			}
+		}
+
+		m.mode = modeNormal
+		m.genreAll = m.pickerAll
+		m.currentGenre = -1
+		m.genreFilter = nil
+		switch len(names) {
+		case 0:
+		case 1:
+			m.currentGenre = m.genreIndex(names[0])
+		default:
+			m.genreFilter = names
+		}
+		m.saveGenres()
+		m.updateVisibleList()
+		m.clearSearch()
+	}
+	return m, nil
+}
+
+func (m Model) renderGenrePicker() string {
+	var lines []string
+
+	lines = append(lines, titleStyle.Render("🏷  Жанры"))
+	logic := "любой из выбранных (ИЛИ)"
+	if m.pickerAll {
+		logic = "все выбранные (И)"
+	}
+	lines = append(lines, dimStyle.Render("  Станция должна иметь: ")+genreStyle.Render(logic))
+	lines = append(lines, strings.Repeat("─", m.width))
+
+	listHeight := m.height - 5
+	if listHeight < 3 {
+		listHeight = 3
+	}
+
+	start := 0
+	if m.pickerCursor >= listHeight {
+		start = m.pickerCursor - listHeight + 1
+	}
+	end := start + listHeight
+	if end > len(m.allGenres) {
+		end = len(m.allGenres)
+	}
+
+	counts := make(map[string]int)
+	for _, s := range m.stations {
+		for _, g := range s.Genres {
+			counts[g.Name]++
+		}
+	}
+
+	for i := start; i < end; i++ {
+		name := m.allGenres[i]
+
+		cursor := "  "
+		style := normalStyle
+		if i == m.pickerCursor {
+			cursor = "▸ "
+			style = selectedStyle
+		}
+
+		check := "[ ] "
+		if m.pickerSelected[name] {
+			check = favoriteStyle.Render("[✓] ")
+		}
+
+		line := fmt.Sprintf("%s%s%-20s %s", cursor, check, name, dimStyle.Render(fmt.Sprint(counts[name])))
+		lines = append(lines, style.Render(line))
+	}
+
+	for len(lines) < listHeight+3 {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, strings.Repeat("─", m.width))
+	lines = append(lines, helpStyle.Render(truncateWidth(" Space выбрать │ a И/ИЛИ │ 0 сбросить │ Enter применить │ Esc отмена", m.width)))
+
+	return strings.Join(lines, "\n")
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/isalikov/radio-record-cli/internal/api"
+	"github.com/isalikov/radio-record-cli/internal/config"
+	"github.com/isalikov/radio-record-cli/internal/player"
+)
+
+func genreModel(cfg *config.Config) Model {
+	stations := []api.Station{
+		{ID: 1, Title: "House", Genres: []api.Genre{{Name: "HOUSE"}}},
+		{ID: 2, Title: "Deep", Genres: []api.Genre{{Name: "HOUSE"}, {Name: "DEEP"}}},
+		{ID: 3, Title: "Techno", Genres: []api.Genre{{Name: "TECHNO"}}},
+		{ID: 4, Title: "Other", Genres: []api.Genre{{Name: "LOUNGE"}}},
+	}
+	genres := []api.Genre{{Name: "TECHNO"}, {Name: "ROCK"}, {Name: "HOUSE"}, {Name: "DEEP"}}
+
+	m := NewModel(nil, player.New(), cfg, nil, nil)
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	next, _ = next.Update(stationsLoadedMsg{stations: stations, genres: genres})
+	return next.(Model)
+}
+
+func press(m Model, keys ...string) Model {
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "ctrl+g":
+			msg = tea.KeyMsg{Type: tea.KeyCtrlG}
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		case "up":
+			msg = tea.KeyMsg{Type: tea.KeyUp}
+		case " ":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		next, _ := m.Update(msg)
+		m = next.(Model)
+	}
+	return m
+}
+
+func visibleIDs(m Model) []int {
+	var ids []int
+	for _, idx := range m.visibleList {
+		ids = append(ids, m.stations[idx].ID)
+	}
+	return ids
+}
+
+func TestGenreOrder(t *testing.T) {
+	m := genreModel(&config.Config{Favorites: []int{}, AlbumArt: "off"})
+
+	// Порядок API, ROCK без станций пропущен, LOUNGE нет в списке API
+	expected := []string{"TECHNO", "HOUSE", "DEEP", "LOUNGE"}
+	if len(m.allGenres) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, m.allGenres)
+	}
+	for i := range expected {
+		if m.allGenres[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, m.allGenres)
+		}
+	}
+}
+
+func TestGenrePicker(t *testing.T) {
+	cfg := &config.Config{Favorites: []int{}, AlbumArt: "off"}
+	m := genreModel(cfg)
+
+	// HOUSE и DEEP через ИЛИ
+	m = press(m, "ctrl+g", "j", " ", "j", " ", "enter")
+	if ids := visibleIDs(m); !equalInts(ids, []int{1, 2}) {
+		t.Errorf("OR: expected stations [1 2], got %v", ids)
+	}
+	if len(cfg.Genres.Names) != 2 || cfg.Genres.All {
+		t.Errorf("Expected filter saved to config, got %+v", cfg.Genres)
+	}
+
+	// То же через И
+	m = press(m, "ctrl+g", "a", "enter")
+	if ids := visibleIDs(m); !equalInts(ids, []int{2}) {
+		t.Errorf("AND: expected stations [2], got %v", ids)
+	}
+
+	// Фильтр восстанавливается при следующем запуске
+	m = genreModel(cfg)
+	if ids := visibleIDs(m); !equalInts(ids, []int{2}) {
+		t.Errorf("Restored: expected stations [2], got %v", ids)
+	}
+
+	// 0 сбрасывает фильтр
+	m = press(m, "0")
+	if len(m.genreFilter) != 0 || len(cfg.Genres.Names) != 0 || len(m.visibleList) != 4 {
+		t.Errorf("Expected filter reset, got %v / %+v", m.genreFilter, cfg.Genres)
+	}
+}
@@ -0,0 +1,517 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/isalikov/radio-record-cli/internal/ui/artwork"
+	"github.com/mattn/go-runewidth"
+)
+
+// layout — раскладка главного экрана в зависимости от размера терминала
+type layout int
+
+const (
+	layoutStandard layout = iota
+	layoutCompact         // узкий терминал: без номеров станций, ссылок и обложки
+	layoutWide            // две панели: список и «сейчас играет» с историей
+	layoutMini            // одна строка для маленьких панелей tmux
+)
+
+// Границы раскладок
+const (
+	compactWidth = 60  // не шире — компактная
+	wideWidth    = 120 // не уже — две панели
+	minWidth     = 30  // уже — мини-плеер: колонкам списка не хватает места
+	minListRows  = 3   // меньше строк списка — блок «сейчас играет» прячется
+
+	// заголовок, вкладки, разделитель, разделитель и строка статуса, подсказка
+	reservedRows = 6
+
+	// ниже — мини-плеер: иначе экран с минимальным списком не помещается
+	miniHeight = reservedRows + minListRows
+)
+
+// layout выбирает раскладку по размеру терминала или режиму мини-плеера
+func (m *Model) layout() layout {
+	switch {
+	case m.mini || m.height < miniHeight || m.width < minWidth:
+		return layoutMini
+	case m.width <= compactWidth:
+		return layoutCompact
+	case m.width >= wideWidth:
+		return layoutWide
+	}
+	return layoutStandard
+}
+
+// listWidth — ширина списка станций; в двух панелях справа остаётся
+// «сейчас играет»
+func (m *Model) listWidth() int {
+	if m.layout() == layoutWide {
+		return m.width * 11 / 20
+	}
+	return m.width
+}
+
+// paneWidth — ширина правой панели за разделителем « │ »
+func (m *Model) paneWidth() int {
+	return m.width - m.listWidth() - 3
+}
+
+// heartColumn — колонка сердечка в строке станции для кликов мышью
+func (m *Model) heartColumn() int {
+	if m.layout() == layoutCompact {
+		return heartCol - 5 // без номера станции
+	}
+	return heartCol
+}
+
+// showNowPlaying — есть ли под списком блок «сейчас играет». Если он не
+// оставляет места списку, блок прячется.
+func (m *Model) showNowPlaying() bool {
+	if m.selected < 0 || m.nowPlaying == nil {
+		return false
+	}
+	switch m.layout() {
+	case layoutStandard, layoutCompact:
+		return m.height-reservedRows-m.nowPlayingHeight() >= minListRows
+	}
+	return false
+}
+
+// nowPlayingHeight — высота блока «сейчас играет»: трек, ссылки и рамка
+func (m *Model) nowPlayingHeight() int {
+	h := 3
+	if m.layout() != layoutCompact {
+		h += len(m.currentLinks())
+	}
+	if m.showSpectrum() {
+		h += vizRows
+	}
+	if m.showCover() && h < coverRows {
+		h = coverRows
+	}
+	return h
+}
+
+// showCover — рисовать ли обложку рядом с блоком «сейчас играет»
+func (m *Model) showCover() bool {
+	return m.cover != "" && m.selected >= 0 && m.nowPlaying != nil && m.layout() != layoutCompact
+}
+
+// truncateWidth обрезает строку до n колонок терминала, добавляя многоточие.
+// Кириллица и широкие символы считаются по ширине, а не по байтам.
+func truncateWidth(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return runewidth.Truncate(s, n, "…")
+}
+
+// padWidth дополняет строку пробелами до n колонок; оформление lipgloss
+// в ширину не входит
+func padWidth(s string, n int) string {
+	if w := lipgloss.Width(s); w < n {
+		return s + strings.Repeat(" ", n-w)
+	}
+	return s
+}
+
+// fitView подгоняет экран окна под терминал: лишние строки снизу
+// отбрасываются, длинные обрезаются вместе с оформлением
+func (m Model) fitView(view string) string {
+	lines := strings.Split(view, "\n")
+	if len(lines) > m.height && m.height > 0 {
+		lines = lines[:m.height]
+	}
+	for i, line := range lines {
+		if lipgloss.Width(line) > m.width {
+			lines[i] = ansi.Truncate(line, m.width, "…")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) renderHeader() string {
+	title := "📻 Radio Record CLI"
+	if m.layout() == layoutCompact {
+		title = "📻 Record"
+	}
+	if m.showFavorites {
+		title += " " + favoriteStyle.Render("[♥ Избранное]")
+	}
+
+	// Громкость справа
+	volStr := dimStyle.Render(qualityLabel(m.quality())+" ") + volumeStyle.Render(fmt.Sprintf("%d%%", m.player.Volume()))
+
+	spacer := m.width - lipgloss.Width(title) - lipgloss.Width(volStr) - 2
+	if spacer < 1 {
+		spacer = 1
+	}
+
+	header := titleStyle.Render(title) + strings.Repeat(" ", spacer) + volStr
+	if !m.showCover() {
+		header = artwork.Clear(m.art.Protocol) + header
+	}
+	return header
+}
+
+// renderStation рисует строку списка шириной width
+func (m Model) renderStation(i, width int) string {
+	stationIdx := m.visibleList[i]
+	station := m.stations[stationIdx]
+	compact := m.layout() == layoutCompact
+
+	cursor := "  "
+	style := normalStyle
+
+	if i == m.cursor {
+		cursor = "▸ "
+		style = selectedStyle
+	}
+
+	if stationIdx == m.selected {
+		cursor = "♪ "
+		style = selectedStyle
+	}
+
+	if m.isMatch(stationIdx) && i != m.cursor {
+		style = matchStyle
+	}
+
+	// Сердечко всегда в одной колонке — по нему можно кликнуть мышью
+	favMark := "  "
+	if m.config.IsFavorite(station.ID) {
+		favMark = favoriteStyle.Render("♥ ")
+	} else if i == m.cursor {
+		favMark = dimStyle.Render("♡ ")
+	}
+
+	hotkey := "    "
+	for idx, favID := range m.config.Favorites {
+		if favID == station.ID && idx < 9 {
+			hotkey = dimStyle.Render(fmt.Sprintf("[%d] ", idx+1))
+			break
+		}
+	}
+
+	// Номер станции (индекс из API, 1-based)
+	prefix := cursor
+	if !compact {
+		prefix += dimStyle.Render(fmt.Sprintf("%3d. ", stationIdx+1))
+	}
+	prefix += hotkey + favMark
+
+	titleWidth := 20
+	if compact {
+		titleWidth = 16
+	}
+	rest := width - lipgloss.Width(prefix) - titleWidth - 1
+	if rest < 0 {
+		titleWidth = max(titleWidth+rest, 0)
+		rest = 0
+	}
+
+	// Колонка «в эфире» забирает часть места у описания. Если на описание
+	// места не остаётся, трек занимает всё вместе с разделителем.
+	onAir := ""
+	tooltipWidth := rest
+	if m.config.ShowOnAir {
+		trackWidth := rest
+		tooltipWidth = 0
+		if !compact && rest*3/5 < rest-1 {
+			trackWidth = rest * 3 / 5
+			tooltipWidth = rest - trackWidth - 1
+		}
+		if track := m.onAir[station.ID]; track != nil {
+			onAir = truncateWidth(trackLabel(track), trackWidth)
+		}
+		onAir = padWidth(onAir, trackWidth)
+	}
+
+	title := truncateWidth(station.Title, titleWidth)
+	tooltip := truncateWidth(station.Tooltip, tooltipWidth)
+	titlePad := strings.Repeat(" ", max(titleWidth-runewidth.StringWidth(title), 0))
+
+	// Подсветка совпадений при поиске
+	if match, ok := m.matches[stationIdx]; ok && m.searchQuery != "" {
+		title = highlightMatch(title, match.title, matchStyle)
+		tooltip = highlightMatch(tooltip, match.tooltip, matchStyle)
+	}
+
+	line := prefix + title + titlePad + " "
+	if m.config.ShowOnAir {
+		line += genreStyle.Render(onAir)
+	}
+	if tooltipWidth > 0 {
+		if m.config.ShowOnAir {
+			line += " "
+		}
+		line += dimStyle.Render(tooltip)
+	}
+	return style.Render(line)
+}
+
+// renderList рисует видимую часть списка станций, дополненную до высоты
+// listHeight
+func (m Model) renderList(width int) []string {
+	listHeight := m.listHeight()
+	start, end := m.listWindow()
+
+	var lines []string
+	if len(m.visibleList) == 0 {
+		lines = append(lines, dimStyle.Render(truncateWidth("  Нет станций для отображения", width)))
+	}
+	for i := start; i < end; i++ {
+		lines = append(lines, m.renderStation(i, width))
+	}
+	for len(lines) < listHeight {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+func (m Model) renderStatus() string {
+	info := fmt.Sprintf(" %d/%d станций", m.cursor+1, len(m.visibleList))
+	if len(m.genreFilter) > 0 {
+		info += " │ " + m.genreFilterLabel()
+	}
+	if m.sort != sortAPI {
+		info += " │ ⇅ " + m.sort.String()
+	}
+	if !m.sleepAt.IsZero() {
+		info += " │ ⏾ " + formatRemaining(time.Until(m.sleepAt))
+	}
+	if len(m.filtered) > 0 {
+		info += fmt.Sprintf(" │ Поиск: %d/%d", m.matchIndex+1, len(m.filtered))
+	}
+	if m.notice != "" {
+		info += " │ " + m.notice
+	}
+
+	// Строка статуса во всю ширину
+	info = padWidth(truncateWidth(info, m.width), m.width)
+	return strings.Repeat("─", m.width) + "\n" + statusBarStyle.Render(info)
+}
+
+// renderNowPlaying рисует блок «сейчас играет» шириной width вместе
+// с обложкой
+func (m Model) renderNowPlaying(width int) string {
+	cover := m.showCover()
+	boxWidth := width - 4 // рамка и отступы
+	if cover {
+		boxWidth -= coverCols + 1
+	}
+
+	npBox := truncateWidth("▶ "+m.trackTitle(), boxWidth-2)
+
+	// Ссылки на музыкальные сервисы
+	if m.layout() != layoutCompact {
+		for _, link := range m.currentLinks() {
+			line := fmt.Sprintf("%-10s%s", link.name+":", link.url)
+			npBox += "\n" + dimStyle.Render(truncateWidth(line, boxWidth-2))
+		}
+	}
+
+	// Спектр под ссылками, чтобы клики по ссылкам попадали в те же строки
+	if m.showSpectrum() {
+		npBox += "\n" + genreStyle.Render(renderSpectrum(m.spectrum, m.vizBands(), vizRows))
+	}
+
+	np := nowPlayingStyle.Width(boxWidth).Render(npBox)
+	if cover {
+		return lipgloss.JoinHorizontal(lipgloss.Top, m.cover, " ", np)
+	}
+	return np
+}
+
+func (m Model) renderFooter() string {
+	switch {
+	case m.mode == modeSearch:
+		return searchStyle.Width(m.width).Render(fmt.Sprintf("/%s▌", m.searchQuery))
+	case m.mode == modeCommand:
+		return m.renderPalette()
+	case m.pendingKey != "":
+		return searchStyle.Width(m.width).Render(truncateWidth(m.pendingHint(), m.width))
+	}
+
+	helpText := "? справка │ / поиск │ ←Tab→ жанры │ 0 сброс │ f ♥ │ +/- 🔊 │ Enter ▶"
+	if m.layout() == layoutCompact {
+		helpText = "? справка │ : команды │ / поиск │ Enter ▶"
+	}
+	helpText = truncateWidth(helpText, m.width)
+	footerPad := (m.width - lipgloss.Width(helpText)) / 2
+	if footerPad < 0 {
+		footerPad = 0
+	}
+	return helpStyle.Render(strings.Repeat(" ", footerPad) + helpText)
+}
+
+// renderMain рисует главный экран в стандартной и компактной раскладке
+func (m Model) renderMain() string {
+	sections := []string{
+		m.renderHeader(),
+		m.renderTabs(),
+		strings.Repeat("─", m.width),
+		strings.Join(m.renderList(m.width), "\n"),
+		m.renderStatus(),
+	}
+	if m.showNowPlaying() {
+		sections = append(sections, m.renderNowPlaying(m.width))
+	}
+	sections = append(sections, m.renderFooter())
+	return strings.Join(sections, "\n")
+}
+
+// renderWide рисует две панели: список слева, «сейчас играет» и историю
+// треков справа
+func (m Model) renderWide() string {
+	listWidth := m.listWidth()
+	left := m.renderList(listWidth)
+	right := m.renderPane(m.paneWidth(), len(left))
+
+	separator := dimStyle.Render("│")
+	body := make([]string, len(left))
+	for i := range left {
+		body[i] = padWidth(left[i], listWidth) + " " + separator + " " + right[i]
+	}
+
+	return strings.Join([]string{
+		m.renderHeader(),
+		m.renderTabs(),
+		strings.Repeat("─", m.width),
+		strings.Join(body, "\n"),
+		m.renderStatus(),
+		m.renderFooter(),
+	}, "\n")
+}
+
+// renderPane рисует правую панель: блок «сейчас играет» и последние треки
+// станции или, если ничего не играет, описание станции под курсором
+func (m Model) renderPane(width, height int) []string {
+	var lines []string
+
+	if m.selected >= 0 && m.nowPlaying != nil {
+		lines = append(lines, strings.Split(m.renderNowPlaying(width), "\n")...)
+		if len(m.history) > 1 {
+			lines = append(lines, "", dimStyle.Render("Последние треки"))
+			for _, track := range m.history[1:] {
+				line := fmt.Sprintf("%-9s %s", track.TimeFormatted, trackLabel(&track))
+				lines = append(lines, truncateWidth(line, width))
+			}
+		}
+	} else if stationIdx := m.getStationAtCursor(); stationIdx >= 0 {
+		station := m.stations[stationIdx]
+		lines = append(lines, titleStyle.Render(truncateWidth(station.Title, width)))
+
+		genres := make([]string, len(station.Genres))
+		for i, g := range station.Genres {
+			genres[i] = g.Name
+		}
+		lines = append(lines, genreStyle.Render(truncateWidth(strings.Join(genres, ", "), width)), "")
+
+		if station.Tooltip != "" {
+			tooltip := lipgloss.NewStyle().Width(width).Render(station.Tooltip)
+			lines = append(lines, strings.Split(tooltip, "\n")...)
+			lines = append(lines, "")
+		}
+		lines = append(lines, dimStyle.Render(truncateWidth("Enter ▶ │ i подробнее", width)))
+	}
+
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// renderMini рисует мини-плеер в одну строку
+func (m Model) renderMini() string {
+	var text string
+	switch {
+	case m.miniOverlay() != "":
+		text = m.miniOverlay()
+	case m.mode == modeSearch:
+		text = "/" + m.searchQuery + "▌"
+	case m.mode == modeCommand:
+		text = ":" + m.palette + "▌"
+	case m.selected >= 0:
+		text = "♪ " + m.stations[m.selected].Title
+		if title := m.trackTitle(); title != "" {
+			text += " │ " + title
+		}
+	case m.getStationAtCursor() >= 0:
+		text = "▸ " + m.stations[m.getStationAtCursor()].Title + " │ Enter ▶"
+	default:
+		text = "📻 Radio Record"
+	}
+	if m.notice != "" {
+		text += " │ " + m.notice
+	}
+
+	vol := fmt.Sprintf("%d%%", m.player.Volume())
+	if !m.sleepAt.IsZero() {
+		vol = "⏾ " + formatRemaining(time.Until(m.sleepAt)) + " " + vol
+	}
+	text = padWidth(truncateWidth(text, m.width-lipgloss.Width(vol)-1), m.width-lipgloss.Width(vol))
+	return artwork.Clear(m.art.Protocol) + titleStyle.Render(text) + volumeStyle.Render(vol)
+}
+
+// miniOverlay — строка мини-плеера для открытого окна: в маленькой панели
+// вместо страницы виден пункт под курсором
+func (m Model) miniOverlay() string {
+	switch m.mode {
+	case modeHelp:
+		return "? Enter ▶ │ s стоп │ +/- 🔊 │ m мини-плеер │ q выход"
+
+	case modeRecent:
+		recent := m.recentStations()
+		if m.recentCursor < len(recent) {
+			return fmt.Sprintf("🕘 %d/%d %s │ Enter ▶", m.recentCursor+1, len(recent), m.stations[recent[m.recentCursor]].Title)
+		}
+		return "🕘 Пока ничего не играло"
+
+	case modeTracks:
+		text := "🎧 " + m.trackQuery + "▌"
+		if results := m.trackResults(); m.trackCursor < len(results) {
+			station := m.stations[results[m.trackCursor].station]
+			text += " │ " + station.Title + " — " + trackLabel(m.onAir[station.ID])
+		}
+		return text
+
+	case modeDetail:
+		station := m.stations[m.detailStation]
+		return "📻 " + station.Title + " │ " + station.Tooltip
+
+	case modeGenres:
+		if m.pickerCursor < len(m.allGenres) {
+			name := m.allGenres[m.pickerCursor]
+			check := "[ ]"
+			if m.pickerSelected[name] {
+				check = "[✓]"
+			}
+			return "🏷 " + check + " " + name + " │ Space выбрать │ Enter применить"
+		}
+
+	case modeEqualizer:
+		if presets := m.config.EqualizerPresets(); m.eqCursor < len(presets) {
+			return "🎚 " + presets[m.eqCursor].Name + " │ Enter для всех │ s для станции"
+		}
+
+	case modeDevices:
+		switch {
+		case m.devicesLoading:
+			return "🔈 Загрузка..."
+		case m.deviceCursor < len(m.devices):
+			return "🔈 " + m.devices[m.deviceCursor].Description + " │ Enter переключить"
+		}
+		return "🔈 Нет устройств"
+	}
+	return ""
+}
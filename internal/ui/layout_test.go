@@ -0,0 +1,275 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/isalikov/radio-record-cli/internal/api"
+	"github.com/isalikov/radio-record-cli/internal/config"
+	"github.com/isalikov/radio-record-cli/internal/player"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "перезаписать golden-файлы в testdata")
+
+func layoutModel(width, height int, playing bool) Model {
+	lipgloss.SetColorProfile(termenv.Ascii)
+
+	titles := []string{"Record", "Deep House", "Техно", "Русский микс", "Супердискотека 90-х", "Chill-Out", "Гоп FM", "Trancemission"}
+	stations := make([]api.Station, len(titles))
+	for i, title := range titles {
+		genre := "HOUSE"
+		if i%3 == 1 {
+			genre = "TECHNO"
+		}
+		stations[i] = api.Station{
+			ID:      i + 1,
+			Prefix:  fmt.Sprintf("st%d", i+1),
+			Title:   title,
+			Tooltip: strings.Repeat("Лучшая музыка круглые сутки. ", i%4+1),
+			Genres:  []api.Genre{{Name: genre}},
+		}
+	}
+
+	cfg := &config.Config{Favorites: []int{2, 5}, AlbumArt: "off"}
+	m := NewModel(nil, player.New(), cfg, nil, nil)
+	next, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	next, _ = next.Update(stationsLoadedMsg{stations: stations, genres: []api.Genre{{Name: "HOUSE"}, {Name: "TECHNO"}}})
+	m = next.(Model)
+
+	if playing {
+		m.selected = 3
+		m.history = []api.Track{
+			{Artist: "Иван Дорн", Song: "Стыцамэн", TimeFormatted: "12:04:10"},
+			{Artist: "Moby", Song: "Porcelain", TimeFormatted: "12:00:31"},
+			{Artist: "Руки Вверх!", Song: "Крошка моя", TimeFormatted: "11:56:02"},
+		}
+		m.nowPlaying = &m.history[0]
+	}
+	return m
+}
+
+func TestLayoutGolden(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		width, height int
+		playing       bool
+		mini          bool
+		viz           bool
+	}{
+		{"compact", 50, 20, false, false, false},
+		{"compact_playing", 50, 20, true, false, false},
+		{"standard", 80, 24, false, false, false},
+		{"standard_playing", 80, 24, true, false, false},
+		{"standard_short", 80, 12, true, false, false},
+		{"standard_visualizer", 80, 24, true, false, true},
+		{"wide", 140, 30, false, false, false},
+		{"wide_playing", 140, 30, true, false, false},
+		{"wide_visualizer", 140, 30, true, false, true},
+		{"mini", 80, 3, true, false, false},
+		{"mini_short", 80, 8, true, false, false},
+		{"mini_narrow", 8, 24, true, false, false},
+		{"mini_toggled", 80, 24, true, true, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := layoutModel(tc.width, tc.height, tc.playing)
+			m.mini = tc.mini
+			if tc.viz {
+				m.config.Visualizer = true
+				m.spectrum = []float64{1, 0.9, 0.75, 0.6, 0.5, 0.5, 0.4, 0.3, 0.35, 0.2, 0.1, 0.05}
+			}
+			view := m.View()
+
+			// Экран не должен вылезать за терминал ни по ширине, ни по высоте
+			lines := strings.Split(view, "\n")
+			if len(lines) > tc.height {
+				t.Errorf("Expected at most %d lines, got %d", tc.height, len(lines))
+			}
+			for i, line := range lines {
+				if w := lipgloss.Width(line); w > tc.width {
+					t.Errorf("Line %d is %d columns wide, expected at most %d: %q", i, w, tc.width, line)
+				}
+			}
+
+			path := filepath.Join("testdata", "layout", tc.name+".golden")
+			if *update {
+				if err := os.WriteFile(path, []byte(view), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Missing golden file, run go test -update: %v", err)
+			}
+			if view != string(expected) {
+				t.Errorf("View differs from %s:\n%s", path, view)
+			}
+		})
+	}
+}
+
+// openOverlay открывает окно с данными для golden-файлов
+func openOverlay(m Model, mode mode) Model {
+	switch mode {
+	case modeRecent:
+		m.config.Recent = []int{4, 2, 7, 1, 5}
+		m.mode = modeRecent
+		m.recentCursor = 1
+	case modeTracks:
+		m.onAir = map[int]*api.Track{}
+		for i := range m.stations {
+			m.onAir[m.stations[i].ID] = &m.history[i%len(m.history)]
+		}
+		m.mode = modeTracks
+	case modeGenres:
+		m.openGenrePicker()
+	case modeDetail:
+		m.mode = modeDetail
+		m.detailStation = m.selected
+		m.detailTracks = m.history
+		m.stations[m.selected].Stream320 = "https://radiorecord.hostingradio.ru/rus96.aacp?quality=320&source=radio-record-cli"
+	case modeEqualizer:
+		m.openEqualizer()
+	case modeDevices:
+		m.mode = modeDevices
+		m.devices = []player.AudioDevice{
+			{Name: "auto", Description: "Autoselect device"},
+			{Name: "pulse/alsa_output.usb-Generic_USB_Audio-00.analog-stereo", Description: "USB Audio Analog Stereo"},
+		}
+	default:
+		m.mode = mode
+	}
+	return m
+}
+
+func TestOverlayGolden(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		width, height int
+		mode          mode
+	}{
+		{"help_compact", 50, 14, modeHelp},
+		{"help_tall", 100, 45, modeHelp},
+		{"recent_compact", 50, 14, modeRecent},
+		{"tracks_compact", 50, 14, modeTracks},
+		{"genres_compact", 50, 14, modeGenres},
+		{"detail_compact", 50, 14, modeDetail},
+		{"equalizer_compact", 50, 14, modeEqualizer},
+		{"devices_compact", 50, 14, modeDevices},
+		{"help_mini", 80, 3, modeHelp},
+		{"recent_mini", 80, 3, modeRecent},
+		{"tracks_mini", 80, 3, modeTracks},
+		{"detail_mini", 80, 3, modeDetail},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := openOverlay(layoutModel(tc.width, tc.height, true), tc.mode)
+			view := m.View()
+
+			lines := strings.Split(view, "\n")
+			if len(lines) > tc.height {
+				t.Errorf("Expected at most %d lines, got %d", tc.height, len(lines))
+			}
+			for i, line := range lines {
+				if w := lipgloss.Width(line); w > tc.width {
+					t.Errorf("Line %d is %d columns wide, expected at most %d: %q", i, w, tc.width, line)
+				}
+			}
+
+			path := filepath.Join("testdata", "overlay", tc.name+".golden")
+			if *update {
+				if err := os.WriteFile(path, []byte(view), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Missing golden file, run go test -update: %v", err)
+			}
+			if view != string(expected) {
+				t.Errorf("View differs from %s:\n%s", path, view)
+			}
+		})
+	}
+}
+
+func TestLayoutOnAirFits(t *testing.T) {
+	for _, width := range []int{30, 45, 60, 61, 80, 140} {
+		m := layoutModel(width, 24, true)
+		m.config.ShowOnAir = true
+		m.onAir = map[int]*api.Track{}
+		for i := range m.stations {
+			m.onAir[m.stations[i].ID] = &m.history[i%len(m.history)]
+		}
+		for i, line := range strings.Split(m.View(), "\n") {
+			if w := lipgloss.Width(line); w > width {
+				t.Errorf("%d columns: line %d is %d wide: %q", width, i, w, line)
+			}
+		}
+	}
+}
+
+func TestTracksLongTitle(t *testing.T) {
+	m := layoutModel(80, 24, true)
+	m.stations[0].Title = "Очень длинное название станции"
+	m = openOverlay(m, modeTracks)
+
+	// Название обрезается до своей колонки, треки остаются на месте
+	for _, line := range strings.Split(m.renderTracks(), "\n") {
+		if !strings.Contains(line, "Очень") {
+			continue
+		}
+		if !strings.Contains(line, "Очень длинное назва… ") {
+			t.Errorf("Expected the title truncated to 20 columns, got %q", line)
+		}
+		if w := lipgloss.Width(line); w > 80 {
+			t.Errorf("Expected the line within 80 columns, got %d", w)
+		}
+		return
+	}
+	t.Error("Expected the station in the track list")
+}
+
+func TestLayoutChoice(t *testing.T) {
+	for _, tc := range []struct {
+		width, height int
+		expected      layout
+	}{
+		{60, 24, layoutCompact},
+		{61, 24, layoutStandard},
+		{119, 40, layoutStandard},
+		{120, 40, layoutWide},
+		{200, 5, layoutMini},
+		{80, 8, layoutMini},
+		{80, 9, layoutStandard},
+		{29, 24, layoutMini},
+		{30, 24, layoutCompact},
+	} {
+		m := Model{width: tc.width, height: tc.height}
+		if got := m.layout(); got != tc.expected {
+			t.Errorf("%dx%d: expected layout %d, got %d", tc.width, tc.height, tc.expected, got)
+		}
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	for _, tc := range []struct {
+		in       string
+		n        int
+		expected string
+	}{
+		{"Техно", 10, "Техно"},
+		{"Русский микс", 8, "Русский…"},
+		{"日本語テキスト", 7, "日本語…"},
+		{"abc", 0, ""},
+	} {
+		if got := truncateWidth(tc.in, tc.n); got != tc.expected {
+			t.Errorf("truncateWidth(%q, %d) = %q, expected %q", tc.in, tc.n, got, tc.expected)
+		}
+	}
+}
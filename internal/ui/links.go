@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/isalikov/radio-record-cli/internal/config"
+)
+
+// trackLink — ссылка на поиск трека в музыкальном сервисе
+type trackLink struct {
+	name string
+	url  string
+}
+
+// trackLinks подставляет трек в шаблоны ссылок из конфига
+func trackLinks(templates []config.Link, artist, song string) []trackLink {
+	r := strings.NewReplacer(
+		"{query}", url.QueryEscape(artist+" "+song),
+		"{artist}", url.QueryEscape(artist),
+		"{song}", url.QueryEscape(song),
+	)
+
+	links := make([]trackLink, len(templates))
+	for i, t := range templates {
+		links[i] = trackLink{name: t.Name, url: r.Replace(t.URL)}
+	}
+	return links
+}
+
+// currentLinks возвращает ссылки для текущего трека
+func (m *Model) currentLinks() []trackLink {
+	if m.nowPlaying == nil {
+		return nil
+	}
+	return trackLinks(m.config.TrackLinks(), m.nowPlayingArtist(), m.nowPlaying.Song)
+}
+
+// openURL открывает ссылку в браузере по умолчанию
+func openURL(link string) error {
+	name := "xdg-open"
+	if runtime.GOOS == "darwin" {
+		name = "open"
+	}
+	cmd := exec.Command(name, link)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// noticeMsg убирает сообщение из строки статуса
+type noticeMsg struct {
+	seq int
+}
+
+const noticeTimeout = 3 * time.Second
+
+// flash показывает сообщение в строке статуса на несколько секунд
+func (m *Model) flash(text string) tea.Cmd {
+	m.notice = text
+	m.noticeSeq++
+	seq := m.noticeSeq
+	return tea.Tick(noticeTimeout, func(time.Time) tea.Msg {
+		return noticeMsg{seq: seq}
+	})
+}
+
+func (m *Model) openLink(link trackLink) tea.Cmd {
+	if err := openURL(link.url); err != nil {
+		return m.flash("Не удалось открыть ссылку: " + err.Error())
+	}
+	return m.flash("Открыто: " + link.name)
+}
+
+// clipboardMsg приносит результат копирования в буфер обмена
+type clipboardMsg struct {
+	text string
+	err  error
+}
+
+// copyText копирует текст в фоне: утилита буфера обмена может думать
+// долго, а Update не должен ждать
+func (m *Model) copyText(text string) tea.Cmd {
+	return func() tea.Msg {
+		return clipboardMsg{text: text, err: copyToClipboard(text)}
+	}
+}
+
+// updatePendingKey завершает команды «o <номер>» и «Y <номер>»
+func (m Model) updatePendingKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	prefix := m.pendingKey
+	m.pendingKey = ""
+
+	key := msg.String()
+	if len(key) != 1 || key[0] < '1' || key[0] > '9' {
+		return m, nil
+	}
+
+	links := m.currentLinks()
+	idx := int(key[0] - '1')
+	if idx >= len(links) {
+		return m, nil
+	}
+
+	if prefix == "o" {
+		return m, m.openLink(links[idx])
+	}
+	return m, m.copyText(links[idx].url)
+}
+
+// pendingHint — подсказка с номерами ссылок после нажатия o или Y
+func (m *Model) pendingHint() string {
+	action := "Открыть"
+	if m.pendingKey == "Y" {
+		action = "Копировать"
+	}
+
+	parts := []string{" " + action + ":"}
+	for i, link := range m.currentLinks() {
+		if i >= 9 {
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", i+1, link.name))
+	}
+	return strings.Join(parts, " │ ") + " │ Esc отмена"
+}
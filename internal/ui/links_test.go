@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/isalikov/radio-record-cli/internal/config"
+)
+
+func TestTrackLinks(t *testing.T) {
+	templates := []config.Link{
+		{Name: "Deezer", URL: "https://www.deezer.com/search/{query}"},
+		{Name: "Discogs", URL: "https://www.discogs.com/search/?q={artist}&type=artist"},
+		{Name: "Bandcamp", URL: "https://bandcamp.com/search?q={song}"},
+	}
+
+	links := trackLinks(templates, "Земфира", "Ариведерчи & Co")
+
+	expected := []string{
+		"https://www.deezer.com/search/%D0%97%D0%B5%D0%BC%D1%84%D0%B8%D1%80%D0%B0+%D0%90%D1%80%D0%B8%D0%B2%D0%B5%D0%B4%D0%B5%D1%80%D1%87%D0%B8+%26+Co",
+		"https://www.discogs.com/search/?q=%D0%97%D0%B5%D0%BC%D1%84%D0%B8%D1%80%D0%B0&type=artist",
+		"https://bandcamp.com/search?q=%D0%90%D1%80%D0%B8%D0%B2%D0%B5%D0%B4%D0%B5%D1%80%D1%87%D0%B8+%26+Co",
+	}
+
+	for i, link := range links {
+		if link.name != templates[i].Name || link.url != expected[i] {
+			t.Errorf("Link %d = %+v, expected %s", i, link, expected[i])
+		}
+	}
+}
+
+func TestDefaultLinks(t *testing.T) {
+	cfg := &config.Config{}
+	links := trackLinks(cfg.TrackLinks(), "John Summit", "Light Years")
+
+	if len(links) != 3 || links[0].url != "https://music.youtube.com/search?q=John+Summit+Light+Years" {
+		t.Errorf("Unexpected default links: %+v", links)
+	}
+}
+
+func TestClipboardResult(t *testing.T) {
+	m := testModel(3)
+
+	next, _ := m.Update(clipboardMsg{text: "https://radiorecord.ru"})
+	m = next.(Model)
+	if m.notice != "Скопировано: https://radiorecord.ru" {
+		t.Errorf("Expected a copied notice, got %q", m.notice)
+	}
+
+	next, _ = m.Update(clipboardMsg{text: "x", err: errors.New("no clipboard")})
+	m = next.(Model)
+	if m.notice != "Не удалось скопировать: no clipboard" {
+		t.Errorf("Expected an error notice, got %q", m.notice)
+	}
+}
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Раскладка экрана для попадания кликом: строки сверху и колонка сердечка
+const (
+	tabsRow     = 1 // строка с вкладками жанров
+	listTop     = 3 // заголовок, вкладки, разделитель
+	heartCol    = 11
+	heartWidth  = 2
+	wheelStep   = 3
+	doubleClick = 400 * time.Millisecond
+)
+
+// listRowAt возвращает индекс в visibleList для строки экрана y или -1
+func (m *Model) listRowAt(y int) int {
+	if y < listTop || y >= listTop+m.listHeight() {
+		return -1
+	}
+	start, end := m.listWindow()
+	i := start + y - listTop
+	if i >= end {
+		return -1
+	}
+	return i
+}
+
+// linkAt возвращает ссылку из блока «сейчас играет» для точки экрана
+func (m *Model) linkAt(x, y int) (trackLink, bool) {
+	var boxTop, left, width int
+	switch {
+	case m.layout() == layoutCompact:
+		// Компактная раскладка рисует блок без ссылок
+		return trackLink{}, false
+	case m.showNowPlaying():
+		// Под списком: разделитель и статус, затем рамка и строка трека
+		boxTop = listTop + m.listHeight() + 2
+		width = m.width
+	case m.layout() == layoutWide && m.selected >= 0 && m.nowPlaying != nil:
+		// Правая панель сразу под разделителем
+		boxTop = listTop
+		left = m.listWidth() + 3
+		width = m.paneWidth()
+	default:
+		return trackLink{}, false
+	}
+
+	// Обложка слева от рамки, ссылки только внутри рамки
+	boxWidth := width - 4
+	if m.showCover() {
+		left += coverCols + 1
+		boxWidth -= coverCols + 1
+	}
+	if x <= left || x > left+boxWidth {
+		return trackLink{}, false
+	}
+
+	links := m.currentLinks()
+	i := y - boxTop - 2
+	if i < 0 || i >= len(links) {
+		return trackLink{}, false
+	}
+	return links[i], true
+}
+
+func (m Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.mode == modeHelp {
+		if msg.Action == tea.MouseActionPress {
+			m.mode = modeNormal
+		}
+		return m, nil
+	}
+	if m.mode != modeNormal {
+		return m, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.cursor -= wheelStep
+		if m.cursor < 0 {
+			m.cursor = 0
+		}
+		return m, nil
+
+	case tea.MouseButtonWheelDown:
+		m.cursor += wheelStep
+		if m.cursor > len(m.visibleList)-1 {
+			m.cursor = len(m.visibleList) - 1
+		}
+		if m.cursor < 0 {
+			m.cursor = 0
+		}
+		return m, nil
+	}
+
+	if msg.Button != tea.MouseButtonLeft || msg.Action != tea.MouseActionPress || m.layout() == layoutMini {
+		return m, nil
+	}
+
+	// Вкладки жанров
+	if msg.Y == tabsRow {
+		_, spans := m.tabsLayout()
+		for _, span := range spans {
+			if msg.X < span.x0 || msg.X >= span.x1 {
+				continue
+			}
+			if span.arrow != 0 {
+				m.setGenre(m.currentGenre + span.arrow)
+			} else {
+				m.setGenre(span.genre)
+			}
+			break
+		}
+		return m, nil
+	}
+
+	// Ссылки в блоке «сейчас играет»
+	if link, ok := m.linkAt(msg.X, msg.Y); ok {
+		return m, m.openLink(link)
+	}
+
+	// В широкой раскладке справа от списка — панель, а не станции
+	if msg.X >= m.listWidth() {
+		return m, nil
+	}
+
+	row := m.listRowAt(msg.Y)
+	if row < 0 {
+		return m, nil
+	}
+	stationIdx := m.visibleList[row]
+
+	// Клик по сердечку
+	if col := m.heartColumn(); msg.X >= col && msg.X < col+heartWidth {
+		m.cursor = row
+		m.toggleFavorite(m.stations[stationIdx].ID)
+		return m, nil
+	}
+
+	// Двойной клик запускает станцию, одиночный — выбирает
+	now := time.Now()
+	isDouble := row == m.cursor && stationIdx == m.lastClickStation && now.Sub(m.lastClick) < doubleClick
+	m.cursor = row
+	m.lastClick = now
+	m.lastClickStation = stationIdx
+
+	if isDouble {
+		m.lastClick = time.Time{}
+		if stationIdx != m.selected {
+			return m, m.playStation(stationIdx)
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/isalikov/radio-record-cli/internal/api"
+	"github.com/isalikov/radio-record-cli/internal/config"
+	"github.com/isalikov/radio-record-cli/internal/player"
+)
+
+func testModel(n int) Model {
+	stations := make([]api.Station, n)
+	for i := range stations {
+		genre := "HOUSE"
+		if i%2 == 1 {
+			genre = "TECHNO"
+		}
+		stations[i] = api.Station{
+			ID:     i + 1,
+			Prefix: fmt.Sprintf("st%d", i+1),
+			Title:  fmt.Sprintf("Station %d", i+1),
+			Genres: []api.Genre{{ID: i % 2, Name: genre}},
+		}
+	}
+
+	m := NewModel(nil, player.New(), &config.Config{Favorites: []int{}, AlbumArt: "off"}, nil, nil)
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	next, _ = next.Update(stationsLoadedMsg{stations: stations})
+	return next.(Model)
+}
+
+func click(m Model, x, y int) Model {
+	next, _ := m.Update(tea.MouseMsg{X: x, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	return next.(Model)
+}
+
+func TestMouseClickRespectsScroll(t *testing.T) {
+	m := testModel(40)
+	m.cursor = 30 // список прокручен вниз
+
+	start, _ := m.listWindow()
+	m = click(m, 20, listTop+2)
+
+	if m.cursor != start+2 {
+		t.Errorf("Expected cursor %d, got %d", start+2, m.cursor)
+	}
+}
+
+func TestMouseWheel(t *testing.T) {
+	m := testModel(40)
+
+	next, _ := m.Update(tea.MouseMsg{Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	m = next.(Model)
+	if m.cursor != wheelStep {
+		t.Errorf("Expected cursor %d after wheel down, got %d", wheelStep, m.cursor)
+	}
+
+	next, _ = m.Update(tea.MouseMsg{Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress})
+	m = next.(Model)
+	if m.cursor != 0 {
+		t.Errorf("Expected cursor 0 after wheel up, got %d", m.cursor)
+	}
+}
+
+func TestMouseClickTab(t *testing.T) {
+	m := testModel(10)
+
+	_, spans := m.tabsLayout()
+	if len(spans) != len(m.allGenres)+1 {
+		t.Fatalf("Expected %d tabs, got %d", len(m.allGenres)+1, len(spans))
+	}
+
+	m = click(m, spans[1].x0, tabsRow)
+	if m.currentGenre != 0 {
+		t.Errorf("Expected genre 0, got %d", m.currentGenre)
+	}
+	if len(m.visibleList) != 5 {
+		t.Errorf("Expected 5 stations in genre, got %d", len(m.visibleList))
+	}
+}
+
+func TestMouseClickHeart(t *testing.T) {
+	m := testModel(10)
+
+	m = click(m, heartCol, listTop+1)
+	if !m.config.IsFavorite(2) {
+		t.Errorf("Expected station 2 to become favorite, got %v", m.config.Favorites)
+	}
+}
+
+func TestMouseClickWidePane(t *testing.T) {
+	m := testModel(10)
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 140, Height: 30})
+	m = next.(Model)
+
+	// Клики по правой панели не выбирают станции
+	m = click(m, m.listWidth()+5, listTop+3)
+	m = click(m, m.listWidth()+5, listTop+3)
+	if m.cursor != 0 || m.selected >= 0 {
+		t.Errorf("Expected a click in the right pane to be ignored, got cursor %d, selected %d", m.cursor, m.selected)
+	}
+
+	m = click(m, m.listWidth()-5, listTop+3)
+	if m.cursor != 3 {
+		t.Errorf("Expected cursor 3 after a click in the list, got %d", m.cursor)
+	}
+}
+
+func TestLinkAt(t *testing.T) {
+	m := layoutModel(80, 24, true)
+	links := m.currentLinks()
+	if len(links) == 0 {
+		t.Fatal("Expected default track links")
+	}
+	boxTop := listTop + m.listHeight() + 2
+
+	if link, ok := m.linkAt(10, boxTop+2); !ok || link.name != links[0].name {
+		t.Errorf("Expected the first link, got %v %v", link, ok)
+	}
+	// Рамка слева — не ссылка
+	if _, ok := m.linkAt(0, boxTop+2); ok {
+		t.Errorf("Expected no link on the border")
+	}
+
+	// Обложка слева от блока
+	m.cover = "cover"
+	if _, ok := m.linkAt(5, boxTop+2); ok {
+		t.Errorf("Expected no link on the cover")
+	}
+
+	// В компактной раскладке ссылок нет: рамка и подсказка не открывают их
+	m = layoutModel(50, 20, true)
+	boxTop = listTop + m.listHeight() + 2
+	for y := boxTop; y < 20; y++ {
+		if link, ok := m.linkAt(10, y); ok {
+			t.Errorf("Expected no link in compact layout at row %d, got %v", y, link)
+		}
+	}
+}
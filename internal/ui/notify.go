@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/isalikov/radio-record-cli/internal/notify"
+)
+
+// Не чаще одного уведомления в notifyInterval — при быстром переключении
+// станций они не должны сыпаться пачкой
+const notifyInterval = 5 * time.Second
+
+// notifyTrack показывает уведомление о смене трека, если терминал не в фокусе.
+// Пока терминал не прислал FocusMsg, фокуса нет: иначе на терминалах без
+// событий фокуса уведомления не появились бы никогда.
+func (m *Model) notifyTrack() tea.Cmd {
+	if m.notifier == nil || m.focused || m.nowPlaying == nil || m.selected < 0 {
+		return nil
+	}
+
+	n := notify.Notification{
+		Title: m.stations[m.selected].Title,
+		Body:  m.trackTitle(),
+	}
+	image := m.nowPlaying.Image100
+	notifier := m.notifier
+	covers := m.covers
+
+	return func() tea.Msg {
+		if image != "" {
+			if path, err := covers.File(image); err == nil {
+				n.Icon = path
+			}
+		}
+		notifier.Notify(n)
+		return nil
+	}
+}
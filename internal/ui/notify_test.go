@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/isalikov/radio-record-cli/internal/api"
+	"github.com/isalikov/radio-record-cli/internal/notify"
+)
+
+type fakeNotifier struct{}
+
+func (fakeNotifier) Notify(notify.Notification) error { return nil }
+
+func TestNotifyFollowsFocus(t *testing.T) {
+	m := testModel(3)
+	m.notifier = fakeNotifier{}
+	m.selected = 0
+	m.nowPlaying = &api.Track{Artist: "Artist", Song: "Song"}
+
+	// Терминал без событий фокуса: уведомления показываются
+	if m.notifyTrack() == nil {
+		t.Errorf("Expected a notification before any focus event")
+	}
+
+	next, _ := m.Update(tea.FocusMsg{})
+	m = next.(Model)
+	if m.notifyTrack() != nil {
+		t.Errorf("Expected no notification while focused")
+	}
+
+	next, _ = m.Update(tea.BlurMsg{})
+	m = next.(Model)
+	if m.notifyTrack() == nil {
+		t.Errorf("Expected a notification after blur")
+	}
+}
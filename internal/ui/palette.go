@@ -0,0 +1,245 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openPalette открывает командную строку «:»
+func (m *Model) openPalette() {
+	m.mode = modeCommand
+	m.palette = ""
+	m.paletteHist = len(m.config.History)
+	m.completions = nil
+}
+
+// paletteActions ищет команды по имени и описанию для подсказки.
+// Точное совпадение имени идёт первым.
+func (m *Model) paletteActions(query string) []*action {
+	type scored struct {
+		a     *action
+		score int
+	}
+	var found []scored
+	for i := range actions {
+		a := &actions[i]
+		best, ok := 0, false
+		for _, q := range queryVariants(query) {
+			for _, text := range []string{a.name, a.help} {
+				score, _, match := fuzzyMatch(q, text)
+				if !match {
+					continue
+				}
+				if text == a.name {
+					score += 1000 // Имя важнее описания
+				}
+				if !ok || score > best {
+					best, ok = score, true
+				}
+			}
+		}
+		if query == "" || ok {
+			found = append(found, scored{a, best})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].score > found[j].score
+	})
+
+	list := make([]*action, len(found))
+	for i, f := range found {
+		list[i] = f.a
+	}
+	return list
+}
+
+// resolveCommand дополняет сокращённое имя команды, если с него
+// начинается ровно одна команда: «:vo 40» выполнит «vol 40». Неоднозначное
+// или неизвестное имя остаётся как есть, execute подскажет варианты.
+func (m *Model) resolveCommand(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || findAction(fields[0]) != nil {
+		return line
+	}
+	var found []string
+	for _, a := range actions {
+		if strings.HasPrefix(a.name, strings.ToLower(fields[0])) {
+			found = append(found, a.name)
+		}
+	}
+	if len(found) != 1 {
+		return line
+	}
+	fields[0] = found[0]
+	return strings.Join(fields, " ")
+}
+
+// suggestCommands — до трёх подходящих команд для подсказки
+func (m *Model) suggestCommands(name string) []string {
+	var names []string
+	for i, a := range m.paletteActions(name) {
+		if i >= 3 {
+			break
+		}
+		names = append(names, a.name)
+	}
+	return names
+}
+
+// completeLine возвращает варианты дополнения последнего слова строки
+// и часть строки перед ним
+func (m *Model) completeLine(line string) (string, []string) {
+	fields := strings.Fields(line)
+	word := ""
+	if len(fields) > 0 && !strings.HasSuffix(line, " ") {
+		word = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+	base := strings.Join(fields, " ")
+	if base != "" {
+		base += " "
+	}
+
+	var candidates []string
+	if len(fields) == 0 {
+		for _, a := range actions {
+			candidates = append(candidates, a.name)
+		}
+		sort.Strings(candidates)
+	} else if a := findAction(fields[0]); a != nil && a.complete != nil {
+		candidates = a.complete(m, fields[1:])
+	}
+
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(strings.ToLower(c), strings.ToLower(word)) {
+			matches = append(matches, c)
+		}
+	}
+	return base, matches
+}
+
+func (m Model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key != "tab" && key != "shift+tab" {
+		m.completions = nil
+	}
+
+	switch key {
+	case "esc", "ctrl+c":
+		m.mode = modeNormal
+
+	case "enter":
+		m.mode = modeNormal
+		line := strings.TrimSpace(m.palette)
+		if line == "" {
+			return m, nil
+		}
+		m.config.AddHistory(line)
+		m.config.Save()
+		return m, m.execute(m.resolveCommand(line))
+
+	case "tab", "shift+tab":
+		if m.completions == nil {
+			base, matches := m.completeLine(m.palette)
+			if len(matches) == 0 {
+				return m, nil
+			}
+			m.completeBase = base
+			m.completions = matches
+			m.completion = 0
+			if key == "shift+tab" {
+				m.completion = len(matches) - 1
+			}
+		} else if key == "tab" {
+			m.completion = (m.completion + 1) % len(m.completions)
+		} else {
+			m.completion = (m.completion + len(m.completions) - 1) % len(m.completions)
+		}
+		m.palette = m.completeBase + m.completions[m.completion]
+		// Единственный вариант — сразу ставим пробел для следующего аргумента
+		if len(m.completions) == 1 {
+			m.palette += " "
+			m.completions = nil
+		}
+
+	case "up", "ctrl+p":
+		if m.paletteHist > 0 {
+			m.paletteHist--
+			m.palette = m.config.History[m.paletteHist]
+		}
+
+	case "down", "ctrl+n":
+		if m.paletteHist < len(m.config.History) {
+			m.paletteHist++
+			m.palette = ""
+			if m.paletteHist < len(m.config.History) {
+				m.palette = m.config.History[m.paletteHist]
+			}
+		}
+
+	case "backspace":
+		if len(m.palette) > 0 {
+			runes := []rune(m.palette)
+			m.palette = string(runes[:len(runes)-1])
+		} else {
+			m.mode = modeNormal
+		}
+
+	default:
+		if utf8.RuneCountInString(key) == 1 {
+			m.palette += key
+		}
+	}
+	return m, nil
+}
+
+// paletteHint — подсказка справа от командной строки: варианты дополнения
+// или подходящие команды
+func (m *Model) paletteHint() string {
+	if len(m.completions) > 1 {
+		parts := make([]string, len(m.completions))
+		for i, c := range m.completions {
+			if i == m.completion {
+				c = "[" + c + "]"
+			}
+			parts[i] = c
+		}
+		return strings.Join(parts, " ")
+	}
+
+	fields := strings.Fields(m.palette)
+	if len(fields) == 0 {
+		return "Tab — команды │ ↑↓ история │ Esc отмена"
+	}
+	if a := findAction(fields[0]); a != nil {
+		return strings.TrimSpace(a.name+" "+a.args) + " — " + a.help
+	}
+
+	found := m.paletteActions(fields[0])
+	if len(found) == 0 {
+		return "нет такой команды"
+	}
+	var parts []string
+	for i, a := range found {
+		if i >= 3 {
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s — %s", a.name, a.help))
+	}
+	return strings.Join(parts, " │ ")
+}
+
+func (m Model) renderPalette() string {
+	line := fmt.Sprintf(":%s▌", m.palette)
+	room := m.width - utf8.RuneCountInString(line) - 2
+	if room < 0 {
+		room = 0
+	}
+	hint := dimStyle.Render("  " + truncateWidth(m.paletteHint(), room))
+	return searchStyle.Width(m.width).Render(line + hint)
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"github.com/isalikov/radio-record-cli/internal/state"
+)
+
+// stopPlayback останавливает воспроизведение и сбрасывает текущую станцию
+func (m *Model) stopPlayback() {
+	m.player.Stop()
+	m.stopViz()
+	m.selected = -1
+	m.nowPlaying = nil
+	m.history = nil
+	m.state.Update(func(s *state.State) {
+		*s = state.State{PID: s.PID, Volume: s.Volume, Favorites: s.Favorites}
+	})
+}
+
+// Close останавливает mpv плеера и визуализатора. Вызывается из main при
+// выходе и по сигналу, когда Update может уже не работать, поэтому
+// состояние модели не трогает.
+func (m Model) Close() {
+	m.player.Stop()
+	m.viz.Stop()
+}
+
+// publishStation сообщает о запуске станции
+func (m *Model) publishStation() {
+	station := m.stations[m.selected]
+	volume := m.player.Volume()
+	m.state.Update(func(s *state.State) {
+		*s = state.State{
+			PID:       s.PID,
+			Playing:   true,
+			StationID: station.ID,
+			Station:   station.Title,
+			Prefix:    station.Prefix,
+			Volume:    volume,
+			Favorites: s.Favorites,
+		}
+	})
+}
+
+// publishTrack сообщает о текущем треке
+func (m *Model) publishTrack() {
+	if m.selected < 0 || m.nowPlaying == nil {
+		return
+	}
+	track := *m.nowPlaying
+	artist := m.nowPlayingArtist()
+	m.state.Update(func(s *state.State) {
+		s.TrackID = track.ID
+		s.Artist = artist
+		s.Song = track.Song
+		s.Image = track.Image100
+	})
+}
+
+func (m *Model) publishVolume() {
+	volume := m.player.Volume()
+	m.state.Update(func(s *state.State) {
+		s.Volume = volume
+	})
+}
+
+func (m *Model) publishFavorites() {
+	favorites := append([]int{}, m.config.Favorites...)
+	m.state.Update(func(s *state.State) {
+		s.Favorites = favorites
+	})
+}
@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// recentStations возвращает индексы недавно игравших станций, которые
+// есть в текущем списке API
+func (m *Model) recentStations() []int {
+	var list []int
+	for _, id := range m.config.Recent {
+		if idx := m.stationIndex(id); idx >= 0 {
+			list = append(list, idx)
+		}
+	}
+	return list
+}
+
+// playPrevious переключает между текущей и предыдущей станцией, как `cd -`.
+// Если ничего не играет — запускает последнюю станцию.
+func (m *Model) playPrevious() tea.Cmd {
+	recent := m.recentStations()
+	if len(recent) == 0 {
+		return nil
+	}
+
+	target := recent[0]
+	if target == m.selected {
+		if len(recent) < 2 {
+			return nil
+		}
+		target = recent[1]
+	}
+	m.jumpToStation(target)
+	return m.playStation(target)
+}
+
+func (m Model) updateRecent(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	recent := m.recentStations()
+
+	switch key := msg.String(); key {
+	case "esc", "r", "q":
+		m.mode = modeNormal
+
+	case "up", "k":
+		if m.recentCursor > 0 {
+			m.recentCursor--
+		}
+
+	case "down", "j":
+		if m.recentCursor < len(recent)-1 {
+			m.recentCursor++
+		}
+
+	case "enter", " ", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		i := m.recentCursor
+		if key[0] >= '1' && key[0] <= '9' {
+			i = int(key[0] - '1')
+		}
+		if i >= len(recent) {
+			return m, nil
+		}
+
+		m.mode = modeNormal
+		stationIdx := recent[i]
+		m.jumpToStation(stationIdx)
+		if stationIdx != m.selected {
+			return m, m.playStation(stationIdx)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) renderRecent() string {
+	var lines []string
+
+	lines = append(lines, titleStyle.Render("🕘 Недавние станции"))
+	lines = append(lines, strings.Repeat("─", m.width))
+
+	recent := m.recentStations()
+	listHeight := m.height - 4
+	if listHeight < 3 {
+		listHeight = 3
+	}
+
+	if len(recent) == 0 {
+		lines = append(lines, dimStyle.Render("  Пока ничего не играло"))
+	}
+
+	// Прокручиваем так, чтобы курсор был виден
+	start := 0
+	if m.recentCursor >= listHeight {
+		start = m.recentCursor - listHeight + 1
+	}
+	for i := start; i < len(recent) && i < start+listHeight; i++ {
+		stationIdx := recent[i]
+		station := m.stations[stationIdx]
+
+		cursor := "  "
+		style := normalStyle
+		if i == m.recentCursor {
+			cursor = "▸ "
+			style = selectedStyle
+		}
+		if stationIdx == m.selected {
+			cursor = "♪ "
+		}
+
+		hotkey := "    "
+		if i < 9 {
+			hotkey = dimStyle.Render(fmt.Sprintf("[%d] ", i+1))
+		}
+
+		// Описание обрезаем до оформления: курсор, номер и название — 27 колонок
+		line := fmt.Sprintf("%s%s%-20s %s", cursor, hotkey, station.Title, dimStyle.Render(truncateWidth(station.Tooltip, m.width-27)))
+		lines = append(lines, style.Render(line))
+	}
+
+	for len(lines) < listHeight+2 {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, strings.Repeat("─", m.width))
+	lines = append(lines, helpStyle.Render(truncateWidth(" ↑↓ выбор │ 1-9 / Enter ▶ │ Esc назад", m.width)))
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,53 @@
+package ui
+
+import "testing"
+
+func selectedID(m Model) int {
+	if m.selected < 0 {
+		return 0
+	}
+	return m.stations[m.selected].ID
+}
+
+func TestPlayPrevious(t *testing.T) {
+	m := testModel(5)
+	defer m.player.Stop()
+
+	for _, idx := range []int{0, 1, 2} {
+		m.playStation(idx)
+	}
+	if !equalInts(m.config.Recent, []int{3, 2, 1}) {
+		t.Fatalf("Expected recent [3 2 1], got %v", m.config.Recent)
+	}
+
+	m = press(m, "b")
+	if selectedID(m) != 2 {
+		t.Errorf("Expected previous station 2, got %d", selectedID(m))
+	}
+	m = press(m, "b")
+	if selectedID(m) != 3 {
+		t.Errorf("Expected to toggle back to station 3, got %d", selectedID(m))
+	}
+}
+
+func TestRecentOverlay(t *testing.T) {
+	m := testModel(5)
+	defer m.player.Stop()
+
+	for _, idx := range []int{4, 0, 2} {
+		m.playStation(idx)
+	}
+
+	m = press(m, "r")
+	if m.mode != modeRecent || m.recentCursor != 1 {
+		t.Fatalf("Expected recent overlay with cursor on the previous station, got mode %d cursor %d", m.mode, m.recentCursor)
+	}
+
+	m = press(m, "3")
+	if m.mode != modeNormal || selectedID(m) != 5 {
+		t.Errorf("Expected station 5 to play, got %d", selectedID(m))
+	}
+	if m.stations[m.getStationAtCursor()].ID != 5 {
+		t.Errorf("Expected cursor on station 5")
+	}
+}
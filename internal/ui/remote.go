@@ -0,0 +1,61 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Команды от внешних источников (HTTP API). Приходят через Program.Send,
+// поэтому обрабатываются в той же горутине, что и клавиши.
+type (
+	remotePlayMsg     struct{ stationID int }
+	remoteStopMsg     struct{}
+	remoteVolumeMsg   struct{ volume int }
+	remoteFavoriteMsg struct{ stationID int }
+)
+
+// Controller forwards remote commands into a running program
+type Controller struct {
+	program *tea.Program
+}
+
+func NewController(p *tea.Program) *Controller {
+	return &Controller{program: p}
+}
+
+func (c *Controller) Play(stationID int)           { c.program.Send(remotePlayMsg{stationID}) }
+func (c *Controller) Stop()                        { c.program.Send(remoteStopMsg{}) }
+func (c *Controller) SetVolume(volume int)         { c.program.Send(remoteVolumeMsg{volume}) }
+func (c *Controller) ToggleFavorite(stationID int) { c.program.Send(remoteFavoriteMsg{stationID}) }
+
+// stationIndex возвращает индекс станции по ID или -1
+func (m *Model) stationIndex(stationID int) int {
+	for i, s := range m.stations {
+		if s.ID == stationID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m Model) updateRemote(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case remotePlayMsg:
+		if idx := m.stationIndex(msg.stationID); idx >= 0 && idx != m.selected {
+			m.jumpToStation(idx)
+			return m, m.playStation(idx)
+		}
+
+	case remoteStopMsg:
+		m.stopPlayback()
+
+	case remoteVolumeMsg:
+		m.player.SetVolume(msg.volume)
+		m.publishVolume()
+
+	case remoteFavoriteMsg:
+		if m.stationIndex(msg.stationID) >= 0 {
+			m.toggleFavorite(msg.stationID)
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/isalikov/radio-record-cli/internal/api"
+)
+
+// SetResume задаёт, что восстановить из прошлой сессии: курсор и режим
+// избранного (restore) и последнюю станцию (autoplay). По умолчанию курсор
+// восстанавливается, а станция — если в конфиге включён autoplay_last.
+func (m *Model) SetResume(restore, autoplay bool) {
+	m.restore = restore
+	m.autoplay = autoplay
+}
+
+// restoreSession восстанавливает прошлую сессию после загрузки станций
+func (m *Model) restoreSession() tea.Cmd {
+	session := m.config.Session
+	if m.restore && session.FavoritesOnly {
+		m.showFavorites = true
+		m.updateVisibleList()
+	}
+
+	// Курсор восстанавливается, только если станция видна с текущими фильтрами
+	if m.restore && session.Cursor != 0 {
+		for i, idx := range m.visibleList {
+			if m.stations[idx].ID == session.Cursor {
+				m.cursor = i
+				break
+			}
+		}
+	}
+
+	if m.autoplay && session.Station != 0 {
+		if idx := m.stationIndex(session.Station); idx >= 0 {
+			return m.playStation(idx)
+		}
+	}
+	return nil
+}
+
+// SaveSession запоминает курсор и режим избранного перед выходом.
+// Последняя станция запоминается при запуске в playStation. main вызывает
+// его для итоговой модели, так что сессия сохраняется и при выходе по
+// сигналу; в конфиг на диск её пишет main.
+func (m *Model) SaveSession() {
+	// Станции не загрузились — прошлая сессия остаётся как была
+	if len(m.stations) == 0 {
+		return
+	}
+	m.config.Session.FavoritesOnly = m.showFavorites
+	m.config.Session.Cursor = 0
+	if idx := m.getStationAtCursor(); idx >= 0 {
+		m.config.Session.Cursor = m.stations[idx].ID
+	}
+}
+
+// cycleQuality переключает качество потока на следующее
+func (m *Model) cycleQuality() tea.Cmd {
+	next := api.Qualities[0]
+	for i, q := range api.Qualities {
+		if q == m.quality() {
+			next = api.Qualities[(i+1)%len(api.Qualities)]
+			break
+		}
+	}
+	return m.setQuality(next)
+}
+
+// setQuality сохраняет качество потока и перезапускает станцию
+func (m *Model) setQuality(quality string) tea.Cmd {
+	m.config.Quality = quality
+	m.config.Save()
+
+	if m.selected >= 0 && m.player.IsPlaying() {
+		station := m.stations[m.selected]
+		if err := m.player.Play(station.StreamURL(quality)); err != nil {
+			m.state.Fail(err)
+		}
+	}
+	return m.flash("Качество: " + qualityLabel(quality))
+}
+
+func (m *Model) quality() string {
+	if m.config.Quality == "" {
+		return api.Quality320
+	}
+	return m.config.Quality
+}
+
+func qualityLabel(quality string) string {
+	if quality == api.QualityHLS {
+		return "HLS"
+	}
+	return quality + "k"
+}
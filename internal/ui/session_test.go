@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/isalikov/radio-record-cli/internal/api"
+	"github.com/isalikov/radio-record-cli/internal/config"
+	"github.com/isalikov/radio-record-cli/internal/player"
+)
+
+func TestSessionRoundTrip(t *testing.T) {
+	cfg := &config.Config{Favorites: []int{2, 3}, AlbumArt: "off"}
+
+	m := genreModel(cfg)
+	m = press(m, "F", "j")
+	m.playStation(m.getStationAtCursor())
+	m = press(m, "q")
+
+	if cfg.Session.Station != 3 || cfg.Session.Cursor != 3 || !cfg.Session.FavoritesOnly {
+		t.Fatalf("Unexpected session %+v", cfg.Session)
+	}
+
+	// Без autoplay восстанавливается только курсор
+	m = genreModel(cfg)
+	if !m.showFavorites || m.stations[m.getStationAtCursor()].ID != 3 {
+		t.Errorf("Expected favorites-only list with cursor on station 3")
+	}
+	if m.selected != -1 {
+		t.Errorf("Expected nothing playing without autoplay, got %d", m.selected)
+	}
+
+	// Выход до загрузки станций не затирает сессию
+	m = NewModel(nil, player.New(), cfg, nil, nil)
+	m.SaveSession()
+	if cfg.Session.Cursor != 3 || !cfg.Session.FavoritesOnly {
+		t.Errorf("Expected the session kept, got %+v", cfg.Session)
+	}
+
+	// --no-resume
+	m = NewModel(nil, player.New(), cfg, nil, nil)
+	m.SetResume(false, false)
+	next, _ := m.Update(stationsLoadedMsg{stations: genreModel(cfg).stations})
+	m = next.(Model)
+	if m.showFavorites || m.cursor != 0 {
+		t.Errorf("Expected fresh start with --no-resume")
+	}
+
+	// autoplay_last
+	cfg.Autoplay = true
+	m = genreModel(cfg)
+	defer m.player.Stop()
+	if m.selected < 0 || m.stations[m.selected].ID != 3 {
+		t.Errorf("Expected station 3 to autoplay, got %d", m.selected)
+	}
+}
+
+func TestCycleQuality(t *testing.T) {
+	m := testModel(3)
+	if m.quality() != api.Quality320 {
+		t.Fatalf("Expected 320 by default, got %s", m.quality())
+	}
+
+	for _, expected := range []string{api.Quality128, api.Quality64, api.QualityHLS, api.Quality320} {
+		m = press(m, "Q")
+		if m.config.Quality != expected {
+			t.Errorf("Expected %s, got %s", expected, m.config.Quality)
+		}
+	}
+}
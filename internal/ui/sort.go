@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"sort"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+type sortMode int
+
+const (
+	sortAPI    sortMode = iota // порядок API, избранные первыми на вкладке «Все»
+	sortTitle                  // по алфавиту
+	sortGenre                  // по первому жанру, затем по алфавиту
+	sortRecent                 // недавно игравшие сверху
+	sortPlays                  // чаще всего игравшие сверху
+)
+
+// sortModes — ключ для конфига и подпись для строки статуса
+var sortModes = []struct {
+	key  string
+	name string
+}{
+	sortAPI:    {"api", "по номеру"},
+	sortTitle:  {"title", "по названию"},
+	sortGenre:  {"genre", "по жанру"},
+	sortRecent: {"recent", "недавние"},
+	sortPlays:  {"plays", "популярные"},
+}
+
+// collator сравнивает названия по правилам русского языка: ё рядом с е,
+// регистр не важен
+var collator = collate.New(language.Russian, collate.IgnoreCase)
+
+func parseSortMode(key string) sortMode {
+	for i, mode := range sortModes {
+		if mode.key == key {
+			return sortMode(i)
+		}
+	}
+	return sortAPI
+}
+
+func (s sortMode) String() string {
+	return sortModes[s].name
+}
+
+func (s sortMode) next() sortMode {
+	return (s + 1) % sortMode(len(sortModes))
+}
+
+// sortVisible упорядочивает visibleList согласно m.sort. Номер станции
+// в списке остаётся индексом из API.
+func (m *Model) sortVisible() {
+	var less func(a, b int) bool
+
+	switch m.sort {
+	case sortTitle:
+		less = func(a, b int) bool {
+			return collator.CompareString(m.stations[a].Title, m.stations[b].Title) < 0
+		}
+
+	case sortGenre:
+		less = func(a, b int) bool {
+			ga, gb := firstGenre(m, a), firstGenre(m, b)
+			if (ga == "") != (gb == "") {
+				return gb == "" // станции без жанра в конце
+			}
+			if c := collator.CompareString(ga, gb); c != 0 {
+				return c < 0
+			}
+			return collator.CompareString(m.stations[a].Title, m.stations[b].Title) < 0
+		}
+
+	case sortRecent:
+		less = func(a, b int) bool {
+			return m.stats.Get(m.stations[a].ID).LastPlayed.After(m.stats.Get(m.stations[b].ID).LastPlayed)
+		}
+
+	case sortPlays:
+		less = func(a, b int) bool {
+			sa, sb := m.stats.Get(m.stations[a].ID), m.stats.Get(m.stations[b].ID)
+			if sa.Plays != sb.Plays {
+				return sa.Plays > sb.Plays
+			}
+			return sa.Seconds > sb.Seconds
+		}
+
+	default:
+		return
+	}
+
+	sort.SliceStable(m.visibleList, func(i, j int) bool {
+		return less(m.visibleList[i], m.visibleList[j])
+	})
+}
+
+// firstGenre возвращает первый жанр станции или пустую строку
+func firstGenre(m *Model, stationIdx int) string {
+	genres := m.stations[stationIdx].Genres
+	if len(genres) == 0 {
+		return ""
+	}
+	return genres[0].Name
+}
+
+// cycleSort переключает режим сортировки на следующий
+func (m *Model) cycleSort() {
+	m.setSort(m.sort.next())
+}
+
+// setSort меняет режим сортировки, оставляя курсор на той же станции
+func (m *Model) setSort(mode sortMode) {
+	current := m.getStationAtCursor()
+
+	m.sort = mode
+	m.config.Sort = sortModes[m.sort].key
+	m.config.Save()
+	m.updateVisibleList()
+
+	for i, idx := range m.visibleList {
+		if idx == current {
+			m.cursor = i
+			break
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/isalikov/radio-record-cli/internal/api"
+	"github.com/isalikov/radio-record-cli/internal/config"
+	"github.com/isalikov/radio-record-cli/internal/player"
+	"github.com/isalikov/radio-record-cli/internal/stats"
+)
+
+func sortedTitles(m Model) []string {
+	var titles []string
+	for _, idx := range m.visibleList {
+		titles = append(titles, m.stations[idx].Title)
+	}
+	return titles
+}
+
+func TestSortModes(t *testing.T) {
+	stations := []api.Station{
+		{ID: 1, Title: "Trancemission", Genres: []api.Genre{{Name: "TRANCE"}}},
+		{ID: 2, Title: "Ёлки", Genres: []api.Genre{{Name: "POP"}}},
+		{ID: 3, Title: "Архив"},
+		{ID: 4, Title: "deep", Genres: []api.Genre{{Name: "HOUSE"}}},
+		{ID: 5, Title: "Европа", Genres: []api.Genre{{Name: "POP"}}},
+	}
+
+	sts := stats.Load("")
+	now := time.Now()
+	sts.RecordPlay(4, now.Add(-time.Hour))
+	sts.RecordPlay(1, now.Add(-2*time.Minute))
+	sts.RecordStop(now.Add(-time.Minute))
+	sts.RecordPlay(4, now)
+	sts.RecordStop(now)
+
+	cfg := &config.Config{Favorites: []int{}, AlbumArt: "off"}
+	m := NewModel(nil, player.New(), cfg, nil, sts)
+	next, _ := m.Update(stationsLoadedMsg{stations: stations})
+	m = next.(Model)
+
+	expected := map[sortMode][]string{
+		sortAPI:    {"Trancemission", "Ёлки", "Архив", "deep", "Европа"},
+		sortTitle:  {"deep", "Trancemission", "Архив", "Европа", "Ёлки"},
+		sortGenre:  {"deep", "Европа", "Ёлки", "Trancemission", "Архив"},
+		sortRecent: {"deep", "Trancemission", "Ёлки", "Архив", "Европа"},
+		sortPlays:  {"deep", "Trancemission", "Ёлки", "Архив", "Европа"},
+	}
+
+	for mode := sortAPI; int(mode) < len(sortModes); mode++ {
+		m.sort = mode
+		m.updateVisibleList()
+		got := sortedTitles(m)
+		for i := range got {
+			if got[i] != expected[mode][i] {
+				t.Errorf("%s: expected %v, got %v", mode, expected[mode], got)
+				break
+			}
+		}
+	}
+}
+
+func TestCycleSortKeepsCursor(t *testing.T) {
+	m := testModel(5)
+	m.cursor = 3 // Station 4
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	m = next.(Model)
+
+	if m.sort != sortTitle || m.config.Sort != "title" {
+		t.Fatalf("Expected title sort, got %s (%q)", m.sort, m.config.Sort)
+	}
+	if m.stations[m.getStationAtCursor()].ID != 4 {
+		t.Errorf("Expected cursor to stay on station 4, got %d", m.stations[m.getStationAtCursor()].ID)
+	}
+}
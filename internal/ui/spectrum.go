@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Визуализатор: около 20 кадров в секунду, пока полосы видны, полосы высотой в три строки
+const (
+	vizInterval = 50 * time.Millisecond
+	vizRows     = 3
+)
+
+// vizBlocks — заполнение ячейки восьмыми долями
+var vizBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// vizFrameMsg приносит уровни полос для следующего кадра
+type vizFrameMsg struct {
+	seq    int
+	levels []float64
+}
+
+// showSpectrum — рисовать ли полосы в блоке «сейчас играет»
+func (m *Model) showSpectrum() bool {
+	return m.config.Visualizer && m.selected >= 0 && m.layout() != layoutMini
+}
+
+// vizBands — число полос: по одной на две колонки внутри блока
+// «сейчас играет», как в renderNowPlaying
+func (m *Model) vizBands() int {
+	width := m.width
+	if m.layout() == layoutWide {
+		width = m.paneWidth()
+	}
+	boxWidth := width - 4
+	if m.showCover() {
+		boxWidth -= coverCols + 1
+	}
+	return (boxWidth - 2) / 2
+}
+
+// vizVisible — видны ли полосы прямо сейчас: окна поверх списка их
+// закрывают
+func (m *Model) vizVisible() bool {
+	switch m.mode {
+	case modeNormal, modeSearch, modeCommand:
+		return m.showSpectrum() && m.viz.Running()
+	}
+	return false
+}
+
+// vizFrame запрашивает следующий кадр. Следующий кадр заказывается только
+// после отрисовки предыдущего, так что кадры не копятся в очереди.
+func (m *Model) vizFrame() tea.Cmd {
+	seq, capture, n := m.vizSeq, m.viz, m.vizBands()
+	gain := float64(m.player.Volume()) / 100
+	return tea.Tick(vizInterval, func(time.Time) tea.Msg {
+		return vizFrameMsg{seq: seq, levels: capture.Levels(n, gain)}
+	})
+}
+
+// startViz подключает визуализатор к играющему mpv: тот копирует звук
+// после эквалайзера и пишет сэмплы в FIFO, полосы из них считает FFT.
+// Вызывается до Play, чтобы фильтр попал в аргументы запуска. Кадры
+// запускает Update, когда полосы видны.
+func (m *Model) startViz() tea.Cmd {
+	if !m.config.Visualizer || m.selected < 0 {
+		return nil
+	}
+	m.vizSeq++
+	m.vizTicking = false
+	m.spectrum = nil
+	filter, err := m.viz.Start()
+	if err == nil {
+		err = m.player.SetTap(filter)
+	}
+	if err != nil {
+		return m.flash("Визуализатор: " + err.Error())
+	}
+	return nil
+}
+
+// stopViz отключает фильтр, а потом закрывает FIFO: mpv не должен писать
+// в закрытый канал
+func (m *Model) stopViz() {
+	m.player.SetTap("")
+	m.viz.Stop()
+	m.vizSeq++
+	m.vizTicking = false
+	m.spectrum = nil
+}
+
+func cmdViz(m *Model, _ []string) tea.Cmd {
+	m.config.Visualizer = !m.config.Visualizer
+	m.config.Save()
+	if !m.config.Visualizer {
+		m.stopViz()
+		return m.flash("Визуализатор выключен")
+	}
+	return tea.Batch(m.startViz(), m.flash("Визуализатор включён"))
+}
+
+// renderSpectrum рисует полосы блочными символами: rows строк, по одной
+// полосе и пробелу на каждое значение
+func renderSpectrum(levels []float64, bands, rows int) string {
+	lines := make([]string, rows)
+	for row := range lines {
+		var b strings.Builder
+		for i := 0; i < bands; i++ {
+			level := 0.0
+			if i < len(levels) {
+				level = levels[i]
+			}
+			// Сколько восьмых долей полосы приходится на эту строку снизу
+			eighths := int(level*float64(rows*8)+0.5) - (rows-1-row)*8
+			if eighths < 0 {
+				eighths = 0
+			}
+			if eighths > 8 {
+				eighths = 8
+			}
+			b.WriteRune(vizBlocks[eighths])
+			b.WriteByte(' ')
+		}
+		lines[row] = strings.TrimRight(b.String(), " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"runtime"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/isalikov/radio-record-cli/internal/config"
+)
+
+func TestRenderSpectrum(t *testing.T) {
+	// Полная полоса занимает обе строки, половина — нижнюю, четверть —
+	// половину нижней. Недостающие значения — пустые полосы.
+	got := renderSpectrum([]float64{1, 0.5, 0.25, 0}, 5, 2)
+	expected := "█\n█ █ ▄"
+	if got != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestVizFramesPauseWhenHidden(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no FIFOs on Windows")
+	}
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	m := genreModel(&config.Config{Favorites: []int{}, AlbumArt: "off", Visualizer: true})
+	m.selected = 0 // Как будто играет первая станция
+	m.startViz()
+	defer m.Close()
+
+	next, cmd := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = next.(Model)
+	if !m.vizTicking || cmd == nil {
+		t.Fatalf("Expected frames to start while the bars are shown")
+	}
+
+	// Справка закрывает полосы: кадр приходит, следующий не заказывается
+	m = press(m, "?")
+	next, _ = m.Update(vizFrameMsg{seq: m.vizSeq, levels: []float64{0.5}})
+	m = next.(Model)
+	if m.vizTicking {
+		t.Errorf("Expected frames paused under the help page")
+	}
+
+	// Старый кадр после перезапуска не порождает второй цепочки
+	m = press(m, "x")
+	if !m.vizTicking {
+		t.Fatalf("Expected frames to resume after closing the help page")
+	}
+	next, _ = m.Update(vizFrameMsg{seq: m.vizSeq - 1})
+	if next.(Model).spectrum == nil {
+		t.Errorf("Expected a stale frame to be ignored")
+	}
+
+	// Мини-плеер полос не рисует
+	m.mini = true
+	next, _ = m.Update(vizFrameMsg{seq: m.vizSeq})
+	if next.(Model).vizTicking {
+		t.Errorf("Expected frames paused in the mini player")
+	}
+}
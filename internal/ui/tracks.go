@@ -0,0 +1,262 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/isalikov/radio-record-cli/internal/api"
+)
+
+// Параметры опроса «что играет» на всех станциях
+const (
+	onAirCacheTTL = 10 * time.Second
+	onAirWorkers  = 8
+	onAirDebounce = 300 * time.Millisecond // Пауза после прокрутки перед запросами
+)
+
+// onAirMsg приносит текущие треки станций (ключ — ID станции).
+// all — результат запроса по всем станциям, а не только по видимым строкам.
+type onAirMsg struct {
+	tracks map[int]*api.Track
+	all    bool
+}
+
+// onAirRefreshMsg срабатывает после паузы в прокрутке списка
+type onAirRefreshMsg struct {
+	seq int
+}
+
+func fetchOnAir(cache *api.NowPlayingCache, stationIDs []int) tea.Cmd {
+	return func() tea.Msg {
+		return onAirMsg{tracks: cache.Fetch(context.Background(), stationIDs), all: true}
+	}
+}
+
+func fetchOnAirContext(ctx context.Context, cache *api.NowPlayingCache, stationIDs []int) tea.Cmd {
+	return func() tea.Msg {
+		return onAirMsg{tracks: cache.Fetch(ctx, stationIDs)}
+	}
+}
+
+// visibleStationIDs возвращает ID станций в строках, видимых на экране
+func (m *Model) visibleStationIDs() []int {
+	start, end := m.listWindow()
+	ids := make([]int, 0, end-start)
+	for _, idx := range m.visibleList[start:end] {
+		ids = append(ids, m.stations[idx].ID)
+	}
+	return ids
+}
+
+// scheduleOnAir откладывает обновление колонки до паузы в прокрутке
+func (m *Model) scheduleOnAir() tea.Cmd {
+	m.onAirSeq++
+	seq := m.onAirSeq
+	return tea.Tick(onAirDebounce, func(time.Time) tea.Msg {
+		return onAirRefreshMsg{seq: seq}
+	})
+}
+
+// refreshOnAir отменяет запросы для ушедших с экрана строк и запрашивает
+// треки для видимых
+func (m *Model) refreshOnAir() tea.Cmd {
+	if m.onAirCancel != nil {
+		m.onAirCancel()
+	}
+	ids := m.visibleStationIDs()
+	if len(ids) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.onAirCancel = cancel
+	return fetchOnAirContext(ctx, m.onAirCache, ids)
+}
+
+func (m *Model) stationIDs() []int {
+	ids := make([]int, len(m.stations))
+	for i, s := range m.stations {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+// trackResult — станция, на которой сейчас играет подходящий трек
+type trackResult struct {
+	station   int
+	score     int
+	positions []int
+}
+
+func trackLabel(t *api.Track) string {
+	artist := t.Artist
+	if artist == "" {
+		artist = "Radio Record"
+	}
+	return artist + " — " + t.Song
+}
+
+// trackResults ищет запрос среди текущих треков всех станций
+func (m *Model) trackResults() []trackResult {
+	var results []trackResult
+	for i, s := range m.stations {
+		track := m.onAir[s.ID]
+		if track == nil {
+			continue
+		}
+
+		if m.trackQuery == "" {
+			results = append(results, trackResult{station: i})
+			continue
+		}
+
+		label := trackLabel(track)
+		best := trackResult{station: i}
+		found := false
+		for _, q := range queryVariants(m.trackQuery) {
+			if score, pos, ok := fuzzyMatch(q, label); ok && (!found || score > best.score) {
+				best.score, best.positions = score, pos
+				found = true
+			}
+		}
+		if found {
+			results = append(results, best)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	return results
+}
+
+// jumpToStation ставит курсор на станцию, при необходимости сбрасывая фильтры
+func (m *Model) jumpToStation(stationIdx int) {
+	for pass := 0; pass < 2; pass++ {
+		for i, idx := range m.visibleList {
+			if idx == stationIdx {
+				m.cursor = i
+				return
+			}
+		}
+		m.currentGenre = -1
+		m.genreFilter = nil
+		m.showFavorites = false
+		m.updateVisibleList()
+		m.clearSearch()
+	}
+}
+
+func (m Model) updateTracks(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = modeNormal
+		m.trackQuery = ""
+
+	case "enter":
+		results := m.trackResults()
+		if m.trackCursor < len(results) {
+			stationIdx := results[m.trackCursor].station
+			m.mode = modeNormal
+			m.trackQuery = ""
+			m.jumpToStation(stationIdx)
+			if stationIdx != m.selected {
+				return m, m.playStation(stationIdx)
+			}
+		}
+
+	case "up", "ctrl+p":
+		if m.trackCursor > 0 {
+			m.trackCursor--
+		}
+
+	case "down", "ctrl+n":
+		if m.trackCursor < len(m.trackResults())-1 {
+			m.trackCursor++
+		}
+
+	case "backspace":
+		if len(m.trackQuery) > 0 {
+			runes := []rune(m.trackQuery)
+			m.trackQuery = string(runes[:len(runes)-1])
+			m.trackCursor = 0
+		}
+
+	default:
+		if utf8.RuneCountInString(msg.String()) == 1 {
+			m.trackQuery += msg.String()
+			m.trackCursor = 0
+		}
+	}
+	return m, nil
+}
+
+func (m Model) renderTracks() string {
+	var lines []string
+
+	title := titleStyle.Render("🎧 Что сейчас играет на всех станциях")
+	lines = append(lines, title)
+	lines = append(lines, searchStyle.Width(m.width).Render(fmt.Sprintf("> %s▌", m.trackQuery)))
+	lines = append(lines, strings.Repeat("─", m.width))
+
+	results := m.trackResults()
+	listHeight := m.height - 5
+	if listHeight < 3 {
+		listHeight = 3
+	}
+
+	if len(results) == 0 {
+		msg := "  Ничего не найдено"
+		if m.onAirLoading {
+			msg = "  ⏳ Загрузка треков..."
+		}
+		lines = append(lines, dimStyle.Render(msg))
+	}
+
+	start := 0
+	if m.trackCursor >= listHeight {
+		start = m.trackCursor - listHeight + 1
+	}
+	end := start + listHeight
+	if end > len(results) {
+		end = len(results)
+	}
+
+	for i := start; i < end; i++ {
+		r := results[i]
+		station := m.stations[r.station]
+
+		cursor := "  "
+		style := normalStyle
+		if i == m.trackCursor {
+			cursor = "▸ "
+			style = selectedStyle
+		}
+		if r.station == m.selected {
+			cursor = "♪ "
+		}
+
+		// Трек обрезаем до подсветки: курсор и название станции — 23 колонки
+		label := highlightMatch(truncateWidth(trackLabel(m.onAir[station.ID]), m.width-23), r.positions, matchStyle)
+		line := cursor + padWidth(truncateWidth(station.Title, 20), 20) + " " + label
+		lines = append(lines, style.Render(line))
+	}
+
+	for len(lines) < listHeight+3 {
+		lines = append(lines, "")
+	}
+
+	info := fmt.Sprintf(" %d станций", len(results))
+	if m.onAirLoading {
+		info += " │ обновление..."
+	}
+	lines = append(lines, strings.Repeat("─", m.width))
+	lines = append(lines, helpStyle.Render(truncateWidth(info+" │ ↑↓ выбор │ Enter ▶ │ Esc назад", m.width)))
+
+	return strings.Join(lines, "\n")
+}
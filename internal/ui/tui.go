@@ -1,8 +1,9 @@
 package ui
 
 import (
+	"context"
 	"fmt"
-	"net/url"
+	"os"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -11,7 +12,12 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/isalikov/radio-record-cli/internal/api"
 	"github.com/isalikov/radio-record-cli/internal/config"
+	"github.com/isalikov/radio-record-cli/internal/notify"
 	"github.com/isalikov/radio-record-cli/internal/player"
+	"github.com/isalikov/radio-record-cli/internal/state"
+	"github.com/isalikov/radio-record-cli/internal/stats"
+	"github.com/isalikov/radio-record-cli/internal/ui/artwork"
+	"github.com/isalikov/radio-record-cli/internal/visualizer"
 )
 
 var (
@@ -73,6 +79,13 @@ const (
 	modeNormal mode = iota
 	modeSearch
 	modeHelp
+	modeTracks
+	modeGenres
+	modeRecent
+	modeCommand
+	modeDetail
+	modeEqualizer
+	modeDevices
 )
 
 type Model struct {
@@ -80,7 +93,10 @@ type Model struct {
 	visibleList   []int
 	allGenres     []string
 	currentGenre  int
+	genreFilter   []string // Несколько жанров из окна выбора, вместо вкладки
+	genreAll      bool     // Станция должна иметь все жанры фильтра
 	filtered      []int
+	matches       map[int]searchMatch
 	cursor        int
 	selected      int
 	player        *player.Player
@@ -95,20 +111,93 @@ type Model struct {
 	searchQuery   string
 	matchIndex    int
 	showFavorites bool
+	sort          sortMode
+	restore       bool         // Восстановить курсор и режим избранного
+	autoplay      bool         // Запустить последнюю станцию
+	stats         *stats.Store // Статистика прослушивания для сортировки
+	onAirCache    *api.NowPlayingCache
+	onAir         map[int]*api.Track // Текущие треки всех станций по ID
+	onAirLoading  bool
+	onAirSeq      int
+	onAirCancel   context.CancelFunc
+	trackQuery    string
+	trackCursor   int
+	art           artwork.Renderer
+	covers        *artwork.Cache
+	coverURL      string
+	cover         string      // Обложка текущего трека, готовая к выводу
+	history       []api.Track // Последние треки играющей станции
+	mini          bool        // Мини-плеер в одну строку
+	viz           *visualizer.Capture
+	spectrum      []float64 // Уровни полос визуализатора
+	vizSeq        int
+	vizTicking    bool // Кадр визуализатора уже заказан
+
+	pickerCursor   int // Окно выбора жанров
+	pickerSelected map[string]bool
+	pickerAll      bool
+	recentCursor   int // Окно недавних станций
+
+	detailStation int // Карточка станции
+	detailTracks  []api.Track
+	detailLoading bool
+	detailErr     error
+
+	eqCursor int // Окно эквалайзера: пресет под курсором
+	eqBand   int // Выбранная полоса
+
+	devices        []player.AudioDevice // Окно аудиоустройств
+	deviceCursor   int
+	devicesLoading bool
+	devicesErr     error
+
+	palette      string    // Командная строка «:»
+	paletteHist  int       // Позиция в истории команд
+	completions  []string  // Варианты дополнения по Tab
+	completion   int       // Выбранный вариант
+	completeBase string    // Строка перед дополняемым словом
+	startup      string    // Команда из аргументов запуска
+	sleepAt      time.Time // Таймер сна, нулевой — выключен
+	sleepSeq     int
+
+	lastClick        time.Time // Для распознавания двойного клика
+	lastClickStation int
+
+	state    *state.Store    // Общее состояние для строки статуса и хуков
+	notifier notify.Notifier // nil, если уведомления выключены
+	focused  bool            // Терминал в фокусе (если он сообщает об этом)
+
+	pendingKey string // Первая клавиша двухклавишной команды (o1, Y2...)
+	notice     string // Короткое сообщение в строке статуса
+	noticeSeq  int
 }
 
 type stationsLoadedMsg struct {
 	stations []api.Station
+	genres   []api.Genre
 	err      error
 }
 
 type nowPlayingMsg struct {
-	track *api.Track
+	track   *api.Track
+	history []api.Track // Последние треки, первым — текущий
 }
 
 type tickMsg time.Time
 
-func NewModel(client *api.Client, p *player.Player, cfg *config.Config) Model {
+func NewModel(client *api.Client, p *player.Player, cfg *config.Config, st *state.Store, sts *stats.Store) Model {
+	protocol, ok := artwork.ParseProtocol(cfg.AlbumArt)
+	if !ok {
+		protocol = artwork.Detect(os.Getenv)
+	}
+
+	var notifier notify.Notifier
+	if cfg.Notify {
+		if n, err := notify.New(); err == nil {
+			notifier = notify.NewRateLimited(n, notifyInterval)
+		}
+	}
+
 	return Model{
 		client:       client,
 		player:       p,
@@ -119,6 +208,17 @@ func NewModel(client *api.Client, p *player.Player, cfg *config.Config) Model {
 		filtered:     []int{},
 		visibleList:  []int{},
 		currentGenre: -1,
+		onAirCache:   api.NewNowPlayingCache(client, onAirCacheTTL, onAirWorkers),
+		onAir:        map[int]*api.Track{},
+		art:          artwork.Renderer{Protocol: protocol},
+		covers:       artwork.NewCache(""),
+		viz:          visualizer.New(),
+		notifier:     notifier,
+		state:        st,
+		stats:        sts,
+		sort:         parseSortMode(cfg.Sort),
+		restore:      true,
+		autoplay:     cfg.Autoplay,
 		width:        80,
 		height:       24,
 	}
@@ -133,15 +233,19 @@ func (m Model) Init() tea.Cmd {
 
 func loadStations(client *api.Client) tea.Cmd {
 	return func() tea.Msg {
-		stations, err := client.GetStations()
-		return stationsLoadedMsg{stations: stations, err: err}
+		stations, genres, err := client.GetStationsWithGenres()
+		return stationsLoadedMsg{stations: stations, genres: genres, err: err}
 	}
 }
 
 func fetchNowPlaying(client *api.Client, stationID int) tea.Cmd {
 	return func() tea.Msg {
-		track, _ := client.GetNowPlaying(stationID)
-		return nowPlayingMsg{track: track}
+		history, _ := client.GetHistory(stationID)
+		msg := nowPlayingMsg{history: history}
+		if len(history) > 0 {
+			msg.track = &history[0]
+		}
+		return msg
 	}
 }
 
@@ -151,24 +255,11 @@ func tickCmd() tea.Cmd {
 	})
 }
 
-func (m *Model) extractGenres() {
-	genreMap := make(map[string]bool)
-	for _, s := range m.stations {
-		for _, g := range s.Genres {
-			genreMap[g.Name] = true
-		}
-	}
-	m.allGenres = []string{}
-	for g := range genreMap {
-		m.allGenres = append(m.allGenres, g)
-	}
-}
-
 func (m *Model) updateVisibleList() {
 	m.visibleList = []int{}
 
 	// На вкладке "Все" (currentGenre == -1) избранные станции идут первыми
-	if m.currentGenre == -1 && !m.showFavorites {
+	if !m.genreFiltered() && !m.showFavorites {
 		// Сначала добавляем избранные
 		for i, s := range m.stations {
 			if m.config.IsFavorite(s.ID) {
@@ -188,23 +279,14 @@ func (m *Model) updateVisibleList() {
 				continue
 			}
 
-			if m.currentGenre >= 0 && m.currentGenre < len(m.allGenres) {
-				genreName := m.allGenres[m.currentGenre]
-				hasGenre := false
-				for _, g := range s.Genres {
-					if g.Name == genreName {
-						hasGenre = true
-						break
-					}
-				}
-				if !hasGenre {
-					continue
-				}
+			if !m.hasGenres(s) {
+				continue
 			}
 
 			m.visibleList = append(m.visibleList, i)
 		}
 	}
+	m.sortVisible()
 
 	if m.cursor >= len(m.visibleList) {
 		m.cursor = 0
@@ -213,18 +295,31 @@ func (m *Model) updateVisibleList() {
 
 func (m *Model) doSearch() {
 	m.filtered = []int{}
+	m.matches = map[int]searchMatch{}
 	if m.searchQuery == "" {
 		return
 	}
 
-	query := strings.ToLower(m.searchQuery)
+	var results []searchMatch
 	for _, idx := range m.visibleList {
 		s := m.stations[idx]
-		title := strings.ToLower(s.Title)
-		tooltip := strings.ToLower(s.Tooltip)
-		if strings.Contains(title, query) || strings.Contains(tooltip, query) {
-			m.filtered = append(m.filtered, idx)
+		genres := make([]string, len(s.Genres))
+		for i, g := range s.Genres {
+			genres[i] = g.Name
 		}
+		match, ok := matchStation(m.searchQuery, s.Title, s.Tooltip, s.Prefix, genres)
+		if !ok {
+			continue
+		}
+		match.station = idx
+		results = append(results, match)
+	}
+
+	// Лучшие совпадения первыми — по ним и ходят n/N
+	rankMatches(results)
+	for _, r := range results {
+		m.filtered = append(m.filtered, r.station)
+		m.matches[r.station] = r
 	}
 
 	if len(m.filtered) > 0 {
@@ -268,49 +363,52 @@ func (m *Model) prevMatch() {
 }
 
 func (m *Model) isMatch(stationIdx int) bool {
-	for _, i := range m.filtered {
-		if i == stationIdx {
-			return true
-		}
-	}
-	return false
+	_, ok := m.matches[stationIdx]
+	return ok
 }
 
 func (m *Model) clearSearch() {
 	m.searchQuery = ""
 	m.filtered = []int{}
+	m.matches = nil
 	m.matchIndex = 0
 }
 
-// highlightMatch подсвечивает вхождения query в text
-func highlightMatch(text, query string, hlStyle lipgloss.Style) string {
-	if query == "" {
+// highlightMatch подсвечивает символы text в позициях positions (индексы рун)
+func highlightMatch(text string, positions []int, hlStyle lipgloss.Style) string {
+	if len(positions) == 0 {
 		return text
 	}
 
-	textLower := strings.ToLower(text)
-	queryLower := strings.ToLower(query)
+	marked := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		marked[p] = true
+	}
 
 	var result strings.Builder
-	lastEnd := 0
+	var run []rune
+	inMatch := false
 
-	for {
-		idx := strings.Index(textLower[lastEnd:], queryLower)
-		if idx == -1 {
-			result.WriteString(text[lastEnd:])
-			break
+	flush := func() {
+		if len(run) == 0 {
+			return
 		}
+		if inMatch {
+			result.WriteString(hlStyle.Render(string(run)))
+		} else {
+			result.WriteString(string(run))
+		}
+		run = run[:0]
+	}
 
-		start := lastEnd + idx
-		end := start + len(query)
-
-		// Добавляем текст до совпадения
-		result.WriteString(text[lastEnd:start])
-		// Добавляем подсвеченное совпадение
-		result.WriteString(hlStyle.Render(text[start:end]))
-
-		lastEnd = end
+	for i, r := range []rune(text) {
+		if marked[i] != inMatch {
+			flush()
+			inMatch = marked[i]
+		}
+		run = append(run, r)
 	}
+	flush()
 
 	return result.String()
 }
@@ -321,8 +419,41 @@ func (m *Model) playStation(stationIdx int) tea.Cmd {
 	}
 	m.selected = stationIdx
 	station := m.stations[stationIdx]
-	m.player.Play(station.Stream320)
-	return fetchNowPlaying(m.client, station.ID)
+	m.applyEqualizer(station.ID)
+	vizCmd := m.startViz()
+	if err := m.player.Play(station.StreamURL(m.quality())); err != nil {
+		m.state.Fail(err)
+	}
+	m.config.Session.Station = station.ID
+	m.config.AddRecent(station.ID)
+	m.publishStation()
+	return tea.Batch(fetchNowPlaying(m.client, station.ID), vizCmd)
+}
+
+// nowPlayingArtist возвращает исполнителя текущего трека; у джинглов
+// и рекламы его нет
+func (m *Model) nowPlayingArtist() string {
+	if m.nowPlaying == nil || m.nowPlaying.Artist == "" {
+		return "Radio Record"
+	}
+	return m.nowPlaying.Artist
+}
+
+// trackTitle возвращает «Исполнитель — Песня» для текущего трека
+func (m *Model) trackTitle() string {
+	if m.nowPlaying == nil {
+		return ""
+	}
+	return m.nowPlayingArtist() + " — " + m.nowPlaying.Song
+}
+
+func (m *Model) toggleFavorite(stationID int) {
+	m.config.ToggleFavorite(stationID)
+	// Обновляем список если в режиме избранного или на вкладке "Все" (где избранные вверху)
+	if m.showFavorites || !m.genreFiltered() {
+		m.updateVisibleList()
+	}
+	m.publishFavorites()
 }
 
 func (m *Model) getStationAtCursor() int {
@@ -332,92 +463,121 @@ func (m *Model) getStationAtCursor() int {
 	return -1
 }
 
+// listHeight — число строк списка станций в текущей раскладке
 func (m *Model) listHeight() int {
-	// header: 2, tabs: 1, separator: 1, status: 2, now playing: 7 (track + 3 links + borders), footer: 1
-	reserved := 14
-	if m.selected < 0 || m.nowPlaying == nil {
-		reserved = 7
+	h := m.height - reservedRows
+	if m.showNowPlaying() {
+		h -= m.nowPlayingHeight()
 	}
-	h := m.height - reserved
-	if h < 5 {
-		h = 5
+	if h < minListRows {
+		h = minListRows
 	}
 	return h
 }
 
-func (m Model) renderTabs() string {
-	var tabs []string
+// listWindow возвращает диапазон visibleList, который помещается на экран
+func (m *Model) listWindow() (int, int) {
+	listHeight := m.listHeight()
 
-	// "Все" tab
-	if m.currentGenre == -1 {
-		tabs = append(tabs, tabActiveStyle.Render("Все"))
-	} else {
-		tabs = append(tabs, tabInactiveStyle.Render("Все"))
+	start := 0
+	if m.cursor >= listHeight {
+		start = m.cursor - listHeight + 1
 	}
 
-	// Genre tabs
-	for i, genre := range m.allGenres {
-		if i == m.currentGenre {
-			tabs = append(tabs, tabActiveStyle.Render(genre))
-		} else {
-			tabs = append(tabs, tabInactiveStyle.Render(genre))
-		}
+	end := start + listHeight
+	if end > len(m.visibleList) {
+		end = len(m.visibleList)
 	}
+	return start, end
+}
 
-	// Join tabs and handle overflow
-	tabsLine := strings.Join(tabs, " ")
+// tabSpan — положение вкладки в строке жанров, нужно для кликов мышью
+type tabSpan struct {
+	genre  int // -1 — вкладка «Все»
+	arrow  int // -1 / +1 для стрелок прокрутки, 0 для вкладок
+	x0, x1 int
+}
 
-	// If tabs are too long, show scrollable view centered on current
-	tabsWidth := lipgloss.Width(tabsLine)
-	if tabsWidth > m.width {
-		// Calculate visible range around current genre
-		var visibleTabs []string
-		currentIdx := m.currentGenre + 1 // +1 because "Все" is index 0
+func (m Model) renderTabs() string {
+	line, _ := m.tabsLayout()
+	return line
+}
 
-		// Build tabs with limited visibility
-		startIdx := currentIdx - 3
-		if startIdx < 0 {
-			startIdx = 0
+// tabsLayout рисует вкладки жанров и возвращает их положение в строке
+func (m Model) tabsLayout() (string, []tabSpan) {
+	renderTab := func(genre int) string {
+		label := "Все"
+		if genre >= 0 {
+			label = m.allGenres[genre]
 		}
-
-		endIdx := startIdx + 7
-		if endIdx > len(m.allGenres)+1 {
-			endIdx = len(m.allGenres) + 1
-			startIdx = endIdx - 7
-			if startIdx < 0 {
-				startIdx = 0
-			}
+		active := genre == m.currentGenre
+		if len(m.genreFilter) > 0 {
+			active = genre >= 0 && containsString(m.genreFilter, label)
 		}
-
-		if startIdx > 0 {
-			visibleTabs = append(visibleTabs, dimStyle.Render("◀"))
+		if active {
+			return tabActiveStyle.Render(label)
 		}
+		return tabInactiveStyle.Render(label)
+	}
 
-		for i := startIdx; i < endIdx && i <= len(m.allGenres); i++ {
-			if i == 0 {
-				if m.currentGenre == -1 {
-					visibleTabs = append(visibleTabs, tabActiveStyle.Render("Все"))
-				} else {
-					visibleTabs = append(visibleTabs, tabInactiveStyle.Render("Все"))
-				}
-			} else {
-				genre := m.allGenres[i-1]
-				if i-1 == m.currentGenre {
-					visibleTabs = append(visibleTabs, tabActiveStyle.Render(genre))
-				} else {
-					visibleTabs = append(visibleTabs, tabInactiveStyle.Render(genre))
-				}
+	var parts []string
+	var spans []tabSpan
+
+	// Вкладки идут с индекса 0 ("Все") до len(allGenres) включительно
+	startIdx, endIdx := 0, len(m.allGenres)+1
+
+	// Если вкладки не помещаются, показываем окно вокруг текущей и
+	// расширяем его, пока хватает ширины вместе со стрелками
+	spanWidth := func(from, to int) int {
+		w := -1
+		for i := from; i < to; i++ {
+			w += lipgloss.Width(renderTab(i-1)) + 1
+		}
+		if from > 0 {
+			w += 2
+		}
+		if to <= len(m.allGenres) {
+			w += 2
+		}
+		return w
+	}
+	if spanWidth(startIdx, endIdx) > m.width {
+		current := m.currentGenre + 1 // +1 because "Все" is index 0
+		startIdx, endIdx = current, current+1
+		for grown := true; grown; {
+			grown = false
+			if endIdx <= len(m.allGenres) && spanWidth(startIdx, endIdx+1) <= m.width {
+				endIdx++
+				grown = true
+			}
+			if startIdx > 0 && spanWidth(startIdx-1, endIdx) <= m.width {
+				startIdx--
+				grown = true
 			}
 		}
+	}
 
-		if endIdx <= len(m.allGenres) {
-			visibleTabs = append(visibleTabs, dimStyle.Render("▶"))
+	add := func(rendered string, span tabSpan) {
+		x := 0
+		if len(parts) > 0 {
+			x = spans[len(spans)-1].x1 + 1
 		}
+		span.x0, span.x1 = x, x+lipgloss.Width(rendered)
+		parts = append(parts, rendered)
+		spans = append(spans, span)
+	}
 
-		tabsLine = strings.Join(visibleTabs, " ")
+	if startIdx > 0 {
+		add(dimStyle.Render("◀"), tabSpan{arrow: -1})
+	}
+	for i := startIdx; i < endIdx; i++ {
+		add(renderTab(i-1), tabSpan{genre: i - 1})
+	}
+	if endIdx <= len(m.allGenres) {
+		add(dimStyle.Render("▶"), tabSpan{arrow: 1})
 	}
 
-	return tabsLine
+	return strings.Join(parts, " "), spans
 }
 
 func (m Model) renderHelp() string {
@@ -443,17 +603,37 @@ func (m Model) renderHelp() string {
   Enter         Применить поиск     Shift+Tab     Предыдущий жанр
   Esc           Отменить/сбросить   f             В избранное
   n             След. совпадение    F             Только избранное
-  N             Пред. совпадение
+  N             Пред. совпадение    Ctrl+G        Выбор жанров
 
   Быстрый доступ                    Прочее
   ─────────────────────────────     ─────────────────────────────
   0             Сбросить фильтры    ?             Показать справку
-  1-9           Избранное #1-9      q / Ctrl+C    Выход`
-
-	footer := dimStyle.Render("\n  Нажми любую клавишу для выхода...")
+  1-9           Избранное #1-9      q / Ctrl+C    Выход
+  t             Поиск по трекам     c             Колонка «в эфире»
+  o 1-9         Открыть ссылку      y             Копировать трек
+  Y 1-9         Копировать ссылку   S             Сортировка
+  Q             Качество потока     b             Предыдущая станция
+  r             Недавние станции    :             Командная строка
+  i             Карточка станции    m             Мини-плеер
+  v             Визуализатор        e             Эквалайзер
+  d             Аудиоустройство`
+
+	// Перевод строки вне оформления: иначе lipgloss дополняет пустую
+	// строку пробелами до ширины подсказки и она вылезает за рамку
+	footer := "\n" + dimStyle.Render("  Нажми любую клавишу для выхода...")
 
 	content := title + "\n\n" + helpBox.Render(help) + footer
 
+	// Рамка не помещается: таблица без неё, лишние строки прячутся над
+	// подсказкой, длинные обрезает fitView
+	if lipgloss.Width(help) > m.width-8 || strings.Count(content, "\n")+1 > m.height {
+		lines := append([]string{title}, strings.Split(help, "\n")...)
+		if len(lines) > m.height-2 && m.height > 2 {
+			lines = lines[:m.height-2]
+		}
+		return strings.Join(lines, "\n") + footer
+	}
+
 	// Center vertically
 	lines := strings.Count(content, "\n") + 1
 	topPadding := (m.height - lines) / 2
@@ -465,6 +645,40 @@ func (m Model) renderHelp() string {
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+
+	next, ok := model.(Model)
+	if !ok {
+		return model, cmd
+	}
+
+	// Колонка «в эфире»: видимые строки изменились — обновим их после паузы
+	if next.config.ShowOnAir && next.mode != modeTracks && !equalInts(m.visibleStationIDs(), next.visibleStationIDs()) {
+		cmd = tea.Batch(cmd, next.scheduleOnAir())
+	}
+
+	// Кадры визуализатора идут, только пока полосы видны: в мини-плеере
+	// и под окнами они встают и возобновляются при возврате
+	if !next.vizTicking && next.vizVisible() {
+		next.vizTicking = true
+		cmd = tea.Batch(cmd, next.vizFrame())
+	}
+	return next, cmd
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if m.mode == modeHelp {
@@ -472,6 +686,34 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.mode == modeTracks {
+			return m.updateTracks(msg)
+		}
+
+		if m.mode == modeGenres {
+			return m.updateGenrePicker(msg)
+		}
+
+		if m.mode == modeRecent {
+			return m.updateRecent(msg)
+		}
+
+		if m.mode == modeCommand {
+			return m.updatePalette(msg)
+		}
+
+		if m.mode == modeDetail {
+			return m.updateDetail(msg)
+		}
+
+		if m.mode == modeEqualizer {
+			return m.updateEqualizer(msg)
+		}
+
+		if m.mode == modeDevices {
+			return m.updateDevices(msg)
+		}
+
 		if m.mode == modeSearch {
 			switch msg.String() {
 			case "enter":
@@ -496,140 +738,129 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		switch msg.String() {
-		case "ctrl+c", "q":
-			m.player.Stop()
-			return m, tea.Quit
-
-		case "/":
-			m.mode = modeSearch
-			m.searchQuery = ""
-
-		case "?":
-			m.mode = modeHelp
+		if m.pendingKey != "" {
+			return m.updatePendingKey(msg)
+		}
 
-		case "esc":
-			m.clearSearch()
+		if line, ok := keymap[msg.String()]; ok {
+			return m, m.execute(line)
+		}
 
-		case "n":
-			m.nextMatch()
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
 
-		case "N":
-			m.prevMatch()
+	case remotePlayMsg, remoteStopMsg, remoteVolumeMsg, remoteFavoriteMsg:
+		return m.updateRemote(msg)
 
-		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
-			}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
 
-		case "down", "j":
-			if m.cursor < len(m.visibleList)-1 {
-				m.cursor++
+	case stationsLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+		} else {
+			m.stations = msg.stations
+			m.extractGenres(msg.genres)
+			m.restoreGenres()
+			m.updateVisibleList()
+			m.publishFavorites()
+			cmd := m.restoreSession()
+			if m.startup != "" {
+				cmd = tea.Batch(cmd, m.execute(m.startup))
+				m.startup = ""
 			}
+			return m, cmd
+		}
 
-		case "g":
-			m.cursor = 0
+	case nowPlayingMsg:
+		prev := m.nowPlaying
+		m.nowPlaying = msg.track
+		m.history = msg.history
+		m.publishTrack()
+		cmds := []tea.Cmd{m.updateCover()}
+		if msg.track != nil && (prev == nil || prev.ID != msg.track.ID) {
+			cmds = append(cmds, m.notifyTrack())
+		}
+		return m, tea.Batch(cmds...)
 
-		case "G":
-			if len(m.visibleList) > 0 {
-				m.cursor = len(m.visibleList) - 1
-			}
+	case tea.FocusMsg:
+		m.focused = true
 
-		case "enter", " ":
-			stationIdx := m.getStationAtCursor()
-			if stationIdx >= 0 {
-				// Toggle: если станция уже играет — останавливаем
-				if stationIdx == m.selected {
-					m.player.Stop()
-					m.selected = -1
-					m.nowPlaying = nil
-					return m, nil
-				}
-				return m, m.playStation(stationIdx)
-			}
+	case tea.BlurMsg:
+		m.focused = false
 
-		case "s":
-			m.player.Stop()
-			m.selected = -1
-			m.nowPlaying = nil
+	case coverMsg:
+		if msg.url == m.coverURL {
+			m.cover = msg.cover
+		}
 
-		case "+", "=":
-			m.player.VolumeUp()
+	case historyMsg:
+		if m.detailStation < len(m.stations) && m.stations[m.detailStation].ID == msg.stationID {
+			m.detailLoading = false
+			m.detailTracks = msg.tracks
+			m.detailErr = msg.err
+		}
 
-		case "-", "_":
-			m.player.VolumeDown()
+	case devicesMsg:
+		m.handleDevices(msg)
 
-		case "tab":
-			m.currentGenre++
-			if m.currentGenre >= len(m.allGenres) {
-				m.currentGenre = -1
-			}
-			m.updateVisibleList()
-			m.clearSearch()
+	case deviceCheckMsg:
+		if msg.err != nil {
+			return m, m.flash("Нет такого устройства: " + msg.name)
+		}
+		return m, m.setDevice(msg.name)
 
-		case "shift+tab":
-			m.currentGenre--
-			if m.currentGenre < -1 {
-				m.currentGenre = len(m.allGenres) - 1
-			}
-			m.updateVisibleList()
-			m.clearSearch()
-
-		case "f":
-			stationIdx := m.getStationAtCursor()
-			if stationIdx >= 0 {
-				m.config.ToggleFavorite(m.stations[stationIdx].ID)
-				// Обновляем список если в режиме избранного или на вкладке "Все" (где избранные вверху)
-				if m.showFavorites || m.currentGenre == -1 {
-					m.updateVisibleList()
-				}
-			}
+	case clipboardMsg:
+		if msg.err != nil {
+			return m, m.flash("Не удалось скопировать: " + msg.err.Error())
+		}
+		return m, m.flash("Скопировано: " + msg.text)
 
-		case "F":
-			m.showFavorites = !m.showFavorites
-			m.updateVisibleList()
-			m.clearSearch()
+	case vizFrameMsg:
+		// Следующий кадр закажет Update, если полосы ещё видны
+		if msg.seq == m.vizSeq {
+			m.vizTicking = false
+			m.spectrum = msg.levels
+		}
 
-		case "0":
-			m.currentGenre = -1
-			m.showFavorites = false
-			m.updateVisibleList()
-			m.clearSearch()
-
-		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
-			idx := int(msg.String()[0] - '1')
-			if idx < len(m.config.Favorites) {
-				favID := m.config.Favorites[idx]
-				for i, s := range m.stations {
-					if s.ID == favID {
-						return m, m.playStation(i)
-					}
-				}
-			}
+	case sleepMsg:
+		if msg.seq == m.sleepSeq {
+			m.sleepAt = time.Time{}
+			m.stopPlayback()
+			return m, m.flash("Таймер сна: остановлено")
 		}
 
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
+	case noticeMsg:
+		if msg.seq == m.noticeSeq {
+			m.notice = ""
+		}
 
-	case stationsLoadedMsg:
-		m.loading = false
-		if msg.err != nil {
-			m.err = msg.err
-		} else {
-			m.stations = msg.stations
-			m.extractGenres()
-			m.updateVisibleList()
+	case onAirRefreshMsg:
+		if msg.seq == m.onAirSeq && m.config.ShowOnAir {
+			return m, m.refreshOnAir()
 		}
 
-	case nowPlayingMsg:
-		m.nowPlaying = msg.track
+	case onAirMsg:
+		if msg.all {
+			m.onAirLoading = false
+		}
+		for id, track := range msg.tracks {
+			m.onAir[id] = track
+		}
 
 	case tickMsg:
 		cmds := []tea.Cmd{tickCmd()}
 		if m.selected >= 0 && m.selected < len(m.stations) {
 			cmds = append(cmds, fetchNowPlaying(m.client, m.stations[m.selected].ID))
 		}
+		if m.mode == modeTracks && !m.onAirLoading {
+			m.onAirLoading = true
+			cmds = append(cmds, fetchOnAir(m.onAirCache, m.stationIDs()))
+		} else if m.config.ShowOnAir {
+			cmds = append(cmds, m.refreshOnAir())
+		}
 		return m, tea.Batch(cmds...)
 	}
 
@@ -652,183 +883,30 @@ func (m Model) View() string {
 		return fmt.Sprintf("Ошибка: %v", m.err)
 	}
 
-	if m.mode == modeHelp {
-		return m.renderHelp()
-	}
-
-	var sections []string
-
-	// === HEADER ===
-	title := "📻 Radio Record CLI"
-	if m.showFavorites {
-		title += " " + favoriteStyle.Render("[♥ Избранное]")
-	}
-
-	// Volume on the right
-	vol := m.player.Volume()
-	volStr := volumeStyle.Render(fmt.Sprintf("%d%%", vol))
-
-	titleLen := lipgloss.Width(title)
-	volLen := lipgloss.Width(volStr)
-	spacer := m.width - titleLen - volLen - 2
-	if spacer < 1 {
-		spacer = 1
-	}
-
-	header := titleStyle.Render(title) + strings.Repeat(" ", spacer) + volStr
-	sections = append(sections, header)
-
-	// === TABS ===
-	sections = append(sections, m.renderTabs())
-	sections = append(sections, strings.Repeat("─", m.width))
-
-	// === STATION LIST ===
-	listHeight := m.listHeight()
-
-	start := 0
-	if m.cursor >= listHeight {
-		start = m.cursor - listHeight + 1
-	}
-
-	end := start + listHeight
-	if end > len(m.visibleList) {
-		end = len(m.visibleList)
+	// В маленькой панели окна сворачиваются в строку мини-плеера
+	if m.layout() == layoutMini {
+		return m.renderMini()
 	}
 
-	var listLines []string
-
-	if len(m.visibleList) == 0 {
-		emptyMsg := "  Нет станций для отображения"
-		listLines = append(listLines, dimStyle.Render(emptyMsg))
+	switch m.mode {
+	case modeHelp:
+		return m.fitView(m.renderHelp())
+	case modeTracks:
+		return m.fitView(m.renderTracks())
+	case modeGenres:
+		return m.fitView(m.renderGenrePicker())
+	case modeRecent:
+		return m.fitView(m.renderRecent())
+	case modeDetail:
+		return m.fitView(m.renderDetail())
+	case modeEqualizer:
+		return m.fitView(m.renderEqualizer())
+	case modeDevices:
+		return m.fitView(m.renderDevices())
 	}
 
-	for i := start; i < end; i++ {
-		stationIdx := m.visibleList[i]
-		station := m.stations[stationIdx]
-
-		cursor := "  "
-		style := normalStyle
-
-		if i == m.cursor {
-			cursor = "▸ "
-			style = selectedStyle
-		}
-
-		if stationIdx == m.selected {
-			cursor = "♪ "
-			style = selectedStyle
-		}
-
-		if m.isMatch(stationIdx) && i != m.cursor {
-			style = matchStyle
-		}
-
-		favMark := ""
-		if m.config.IsFavorite(station.ID) {
-			favMark = favoriteStyle.Render("♥ ")
-		}
-
-		hotkey := ""
-		for idx, favID := range m.config.Favorites {
-			if favID == station.ID && idx < 9 {
-				hotkey = dimStyle.Render(fmt.Sprintf("[%d] ", idx+1))
-				break
-			}
-		}
-
-		// Номер станции (индекс из API, 1-based)
-		stationNum := dimStyle.Render(fmt.Sprintf("%3d. ", stationIdx+1))
-
-		// Truncate tooltip if needed
-		maxTitleLen := 20
-		maxTooltipLen := m.width - maxTitleLen - 20 // уменьшаем для номера станции
-		if maxTooltipLen < 10 {
-			maxTooltipLen = 10
-		}
-
-		tooltip := station.Tooltip
-		if len(tooltip) > maxTooltipLen {
-			tooltip = tooltip[:maxTooltipLen-3] + "..."
-		}
-
-		// Подсветка совпадений при поиске
-		title := station.Title
-		if m.searchQuery != "" && m.isMatch(stationIdx) {
-			title = highlightMatch(station.Title, m.searchQuery, matchStyle)
-			tooltip = highlightMatch(tooltip, m.searchQuery, matchStyle)
-		}
-
-		line := fmt.Sprintf("%s%s%s%s%-*s %s", cursor, stationNum, hotkey, favMark, maxTitleLen, title, dimStyle.Render(tooltip))
-		listLines = append(listLines, style.Render(line))
-	}
-
-	// Pad list to fixed height
-	for len(listLines) < listHeight {
-		listLines = append(listLines, "")
-	}
-
-	sections = append(sections, strings.Join(listLines, "\n"))
-
-	// === STATUS BAR ===
-	sections = append(sections, strings.Repeat("─", m.width))
-
-	info := fmt.Sprintf(" %d/%d станций", m.cursor+1, len(m.visibleList))
-	if len(m.filtered) > 0 {
-		info += fmt.Sprintf(" │ Поиск: %d/%d", m.matchIndex+1, len(m.filtered))
+	if m.layout() == layoutWide {
+		return m.renderWide()
 	}
-
-	// Pad status bar to full width
-	infoLen := lipgloss.Width(info)
-	if infoLen < m.width {
-		info += strings.Repeat(" ", m.width-infoLen)
-	}
-	sections = append(sections, statusBarStyle.Render(info))
-
-	// === NOW PLAYING ===
-	if m.selected >= 0 && m.nowPlaying != nil {
-		artist := m.nowPlaying.Artist
-		song := m.nowPlaying.Song
-		if artist == "" {
-			artist = "Radio Record"
-		}
-
-		npContent := fmt.Sprintf("▶ %s — %s", artist, song)
-
-		// Truncate if too long
-		maxNpLen := m.width - 6
-		if len(npContent) > maxNpLen {
-			npContent = npContent[:maxNpLen-3] + "..."
-		}
-
-		// Music service links
-		query := url.QueryEscape(artist + " " + song)
-		ytLink := fmt.Sprintf("https://music.youtube.com/search?q=%s", query)
-		yaLink := fmt.Sprintf("https://music.yandex.ru/search?text=%s", query)
-		spLink := fmt.Sprintf("https://open.spotify.com/search/%s", query)
-
-		linksLine := dimStyle.Render(fmt.Sprintf("YT Music: %s", ytLink))
-		linksLine2 := dimStyle.Render(fmt.Sprintf("Yandex:   %s", yaLink))
-		linksLine3 := dimStyle.Render(fmt.Sprintf("Spotify:  %s", spLink))
-
-		npBox := npContent + "\n" + linksLine + "\n" + linksLine2 + "\n" + linksLine3
-		np := nowPlayingStyle.Width(m.width - 4).Render(npBox)
-		sections = append(sections, np)
-	}
-
-	// === FOOTER ===
-	var footer string
-	if m.mode == modeSearch {
-		searchLine := fmt.Sprintf("/%s▌", m.searchQuery)
-		footer = searchStyle.Width(m.width).Render(searchLine)
-	} else {
-		helpText := "? справка │ / поиск │ ←Tab→ жанры │ 0 сброс │ f ♥ │ +/- 🔊 │ Enter ▶"
-		footerPad := (m.width - lipgloss.Width(helpText)) / 2
-		if footerPad < 0 {
-			footerPad = 0
-		}
-		footer = helpStyle.Render(strings.Repeat(" ", footerPad) + helpText)
-	}
-	sections = append(sections, footer)
-
-	return strings.Join(sections, "\n")
+	return m.renderMain()
 }
@@ -0,0 +1,168 @@
+// Package visualizer taps decoded audio of the playing mpv and turns it
+// into spectrum bars.
+package visualizer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/isalikov/radio-record-cli/internal/rundir"
+)
+
+// Bars fall by this fraction of full height per frame instead of dropping
+// to the new level at once
+const falloff = 0.08
+
+// staleAfter is how long buffered samples stay valid without updates: mpv
+// stops writing when the stream stalls or playback stops
+const staleAfter = 250 * time.Millisecond
+
+const bufferSize = 4 * WindowSize
+
+// Capture reads the samples that the playing mpv writes to a FIFO, see
+// Filter. A nil *Capture is valid and never produces any levels.
+type Capture struct {
+	mu      sync.Mutex
+	file    *os.File
+	path    string
+	samples []float64 // Ring buffer of the latest samples
+	pos     int
+	filled  bool
+	updated time.Time
+	levels  []float64 // Last levels for the falloff
+}
+
+// New returns a stopped capture
+func New() *Capture {
+	return &Capture{samples: make([]float64, bufferSize)}
+}
+
+// Start creates the FIFO in the runtime directory and starts reading it.
+// It returns the audio filter to pass to the player. A running capture
+// keeps its FIFO, so switching stations only changes the writer.
+func (c *Capture) Start() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		dir, err := rundir.Ensure()
+		if err != nil {
+			return "", err
+		}
+		path := filepath.Join(dir, fmt.Sprintf("radiorecord-viz-%d.fifo", os.Getpid()))
+		os.Remove(path)
+		if err := mkfifo(path); err != nil {
+			return "", err
+		}
+
+		// Opened for reading and writing: opening does not wait for mpv,
+		// and mpv never gets EPIPE between stations
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			os.Remove(path)
+			return "", err
+		}
+		c.file, c.path = f, path
+		go c.read(f)
+	}
+	return Filter(c.path), nil
+}
+
+// read fills the ring buffer until the FIFO is closed. Lines of two mpv
+// instances may interleave during a crossfade, which only mixes the two
+// stations for a moment.
+func (c *Capture) read(f *os.File) {
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		sample, ok := parseLine(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		c.mu.Lock()
+		if c.file != f {
+			c.mu.Unlock()
+			return
+		}
+		c.write(sample)
+		c.updated = time.Now()
+		c.mu.Unlock()
+	}
+}
+
+func (c *Capture) write(sample float64) {
+	c.samples[c.pos] = sample
+	c.pos = (c.pos + 1) % len(c.samples)
+	if c.pos == 0 {
+		c.filled = true
+	}
+}
+
+// Stop closes and removes the FIFO. The player must drop the filter first.
+func (c *Capture) Stop() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file != nil {
+		c.file.Close()
+		os.Remove(c.path)
+	}
+	c.file = nil
+	c.pos = 0
+	c.filled = false
+	for i := range c.samples {
+		c.samples[i] = 0
+	}
+	c.updated = time.Time{}
+	c.levels = nil
+}
+
+// Running reports whether the capture is started
+func (c *Capture) Running() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.file != nil
+}
+
+// Levels returns n bar heights in 0..1 for the latest window of audio.
+// gain is the player volume, 1 for 100%, so the bars follow what is heard.
+// Bars rise at once and fall off smoothly between calls.
+func (c *Capture) Levels(n int, gain float64) []float64 {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	window := make([]float64, WindowSize)
+	if (c.filled || c.pos >= WindowSize) && time.Since(c.updated) <= staleAfter {
+		for i := range window {
+			window[i] = gain * c.samples[(c.pos-WindowSize+i+len(c.samples))%len(c.samples)]
+		}
+	}
+	prev := c.levels
+	c.mu.Unlock()
+
+	levels := Bands(window, SampleRate, n)
+	if len(prev) == n {
+		for i, l := range levels {
+			if fallen := prev[i] - falloff; l < fallen {
+				levels[i] = fallen
+			}
+		}
+	}
+
+	c.mu.Lock()
+	c.levels = levels
+	c.mu.Unlock()
+	return levels
+}
@@ -0,0 +1,7 @@
+package visualizer
+
+import "errors"
+
+func mkfifo(path string) error {
+	return errors.New("visualizer is not supported on Windows")
+}
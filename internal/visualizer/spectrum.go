@@ -0,0 +1,107 @@
+package visualizer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PCM format of the tap: mono samples at SampleRate, WindowSize samples per
+// FFT, about 46 ms
+const (
+	SampleRate = 11025
+	WindowSize = 512
+)
+
+// Spectrum range shown by the bars. The tap's sample rate limits the top
+// to just below 5.5 kHz.
+const (
+	minFreq = 40.0
+	maxFreq = 5000.0
+	floorDB = -60.0 // Silence
+	rangeDB = 50.0  // From silence to a full bar
+)
+
+// Filter returns the mpv audio filter that taps the playing audio: a copy
+// is downmixed and resampled, and every decoded sample is written to path
+// as a line of text. lavfi has no filter that writes raw audio to a file,
+// so each sample becomes a frame of its own and astats reports its value.
+// The audio itself passes through unchanged, so the tap goes after the
+// equalizer and sees what is played.
+//
+// The graph is quoted with mpv's %length% syntax because it contains
+// brackets and commas.
+func Filter(path string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "asplit[viz_out][viz_in];[viz_in]aresample=%d,aformat=channel_layouts=mono,", SampleRate)
+	b.WriteString("asetnsamples=n=1:p=0,astats=metadata=1:reset=1:measure_perchannel=DC_offset:measure_overall=none,")
+	fmt.Fprintf(&b, "ametadata=mode=print:file='%s':direct=1,anullsink;[viz_out]anull", path)
+
+	graph := b.String()
+	return fmt.Sprintf("lavfi=%%%d%%%s", len(graph), graph)
+}
+
+// parseLine parses a line printed by the tap: the mean of a one-sample
+// frame is the sample, so "lavfi.astats.1.DC_offset=-0.25" is -0.25.
+func parseLine(line string) (float64, bool) {
+	value, found := strings.CutPrefix(line, "lavfi.astats.1.DC_offset=")
+	if !found {
+		return 0, false
+	}
+	sample, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(sample) || math.IsInf(sample, 0) {
+		return 0, false
+	}
+	return sample, true
+}
+
+// Bands splits the spectrum of samples into n logarithmically spaced bands
+// between 40 Hz and 5 kHz and returns their levels in 0..1. len(samples)
+// must be a power of two.
+func Bands(samples []float64, sampleRate, n int) []float64 {
+	size := len(samples)
+	x := make([]complex128, size)
+	for i, s := range samples {
+		// Hann window against leakage between bands
+		w := 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(size-1))
+		x[i] = complex(s*w, 0)
+	}
+	fft(x)
+
+	binHz := float64(sampleRate) / float64(size)
+	levels := make([]float64, n)
+	for band := range levels {
+		lo := minFreq * math.Pow(maxFreq/minFreq, float64(band)/float64(n))
+		hi := minFreq * math.Pow(maxFreq/minFreq, float64(band+1)/float64(n))
+
+		from := int(lo / binHz)
+		to := int(hi / binHz)
+		if to <= from {
+			to = from + 1
+		}
+		if to > size/2 {
+			to = size / 2
+		}
+
+		peak := 0.0
+		for i := from; i < to; i++ {
+			// Amplitude of a full-scale sine is 1 after the window's gain of 1/2
+			mag := 4 * cmplxAbs(x[i]) / float64(size)
+			if mag > peak {
+				peak = mag
+			}
+		}
+
+		level := 0.0
+		if peak > 0 {
+			level = (20*math.Log10(peak) - floorDB) / rangeDB
+		}
+		levels[band] = math.Max(0, math.Min(1, level))
+	}
+	return levels
+}
+
+func cmplxAbs(c complex128) float64 {
+	return math.Hypot(real(c), imag(c))
+}
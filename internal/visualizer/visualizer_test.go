@@ -0,0 +1,212 @@
+package visualizer
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sine(freq, amplitude float64) []float64 {
+	samples := make([]float64, WindowSize)
+	for i := range samples {
+		samples[i] = amplitude * math.Sin(2*math.Pi*freq*float64(i)/SampleRate)
+	}
+	return samples
+}
+
+func TestFilter(t *testing.T) {
+	filter := Filter("/run/user/1000/radio-record-cli/viz.fifo")
+
+	// mpv reads exactly the announced number of bytes as the graph
+	rest, ok := strings.CutPrefix(filter, "lavfi=%")
+	if !ok {
+		t.Fatalf("Expected a quoted lavfi filter, got %q", filter)
+	}
+	length, graph, _ := strings.Cut(rest, "%")
+	if n, err := strconv.Atoi(length); err != nil || n != len(graph) {
+		t.Errorf("Expected length %d, got %s", len(graph), length)
+	}
+
+	for _, expected := range []string{
+		"asplit[viz_out][viz_in];",
+		"aresample=11025,aformat=channel_layouts=mono",
+		"asetnsamples=n=1:p=0",
+		"measure_perchannel=DC_offset",
+		"file='/run/user/1000/radio-record-cli/viz.fifo':direct=1",
+	} {
+		if !strings.Contains(graph, expected) {
+			t.Errorf("Expected %q in the graph", expected)
+		}
+	}
+	// The unchanged audio is the only output
+	if !strings.HasSuffix(graph, "anullsink;[viz_out]anull") {
+		t.Errorf("Expected the audio passed through, got %q", graph)
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line   string
+		sample float64
+		ok     bool
+	}{
+		{"lavfi.astats.1.DC_offset=-0.250000", -0.25, true},
+		{"lavfi.astats.1.DC_offset=0.000000", 0, true},
+		{"lavfi.astats.1.DC_offset=nan", 0, false},
+		{"lavfi.astats.2.DC_offset=0.5", 0, false},
+		{"frame:12   pts:12345   pts_time:0.28", 0, false},
+	}
+	for _, tt := range tests {
+		sample, ok := parseLine(tt.line)
+		if ok != tt.ok || (ok && sample != tt.sample) {
+			t.Errorf("parseLine(%q) = %f, %v", tt.line, sample, ok)
+		}
+	}
+}
+
+func TestFFT(t *testing.T) {
+	// A sine exactly in bin 8
+	x := make([]complex128, 64)
+	for i := range x {
+		x[i] = complex(math.Sin(2*math.Pi*8*float64(i)/64), 0)
+	}
+	fft(x)
+
+	for i := 0; i < 32; i++ {
+		mag := cmplxAbs(x[i])
+		if i == 8 && math.Abs(mag-32) > 1e-9 {
+			t.Errorf("Expected magnitude 32 in bin 8, got %f", mag)
+		}
+		if i != 8 && mag > 1e-9 {
+			t.Errorf("Expected empty bin %d, got %f", i, mag)
+		}
+	}
+}
+
+func TestBands(t *testing.T) {
+	levels := Bands(sine(1000, 0.5), SampleRate, 16)
+
+	loudest := 0
+	for i, l := range levels {
+		if l > levels[loudest] {
+			loudest = i
+		}
+	}
+
+	// 1 kHz on the log scale from 40 Hz to 5 kHz
+	expected := int(16 * math.Log(1000/minFreq) / math.Log(maxFreq/minFreq))
+	if loudest != expected {
+		t.Errorf("Expected band %d to be the loudest, got %d: %v", expected, loudest, levels)
+	}
+	if levels[loudest] < 0.8 {
+		t.Errorf("Expected a high bar for a loud sine, got %f", levels[loudest])
+	}
+	if levels[0] > 0.2 {
+		t.Errorf("Expected the lowest band to stay quiet, got %f", levels[0])
+	}
+
+	for i, l := range Bands(make([]float64, WindowSize), SampleRate, 16) {
+		if l != 0 {
+			t.Errorf("Expected silence in band %d, got %f", i, l)
+		}
+	}
+}
+
+func TestLevelsFalloff(t *testing.T) {
+	c := New()
+	for _, s := range sine(1000, 0.5) {
+		c.write(s)
+	}
+	c.updated = time.Now()
+	loud := c.Levels(8, 1)
+
+	// No updates from mpv: bars fall off instead of freezing
+	c.updated = time.Now().Add(-time.Second)
+	quiet := c.Levels(8, 1)
+	for i := range loud {
+		if expected := math.Max(0, loud[i]-falloff); math.Abs(quiet[i]-expected) > 1e-9 {
+			t.Errorf("Band %d: expected %f after falloff, got %f", i, expected, quiet[i])
+		}
+	}
+
+	var nilCapture *Capture
+	if nilCapture.Levels(8, 1) != nil || nilCapture.Running() {
+		t.Errorf("Expected a nil capture to be empty")
+	}
+}
+
+func TestLevelsGain(t *testing.T) {
+	// Quiet enough that the bar is not clipped at full height
+	c := New()
+	for _, s := range sine(1000, 0.1) {
+		c.write(s)
+	}
+	c.updated = time.Now()
+	full := maxLevel(c.Levels(8, 1))
+
+	// Half the volume is 6 dB lower, zero volume is silence
+	c.levels = nil
+	if half := maxLevel(c.Levels(8, 0.5)); math.Abs(full-half-20*math.Log10(2)/rangeDB) > 1e-9 {
+		t.Errorf("Expected the volume to lower the bars, got %f and %f", full, half)
+	}
+	c.levels = nil
+	if muted := maxLevel(c.Levels(8, 0)); muted != 0 {
+		t.Errorf("Expected no bars at zero volume, got %f", muted)
+	}
+}
+
+func maxLevel(levels []float64) float64 {
+	peak := 0.0
+	for _, l := range levels {
+		peak = math.Max(peak, l)
+	}
+	return peak
+}
+
+func TestCaptureFIFO(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no FIFOs on Windows")
+	}
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	c := New()
+	filter, err := c.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+	if !strings.Contains(filter, c.path) {
+		t.Errorf("Expected the filter to write to %s", c.path)
+	}
+
+	// mpv opens the FIFO for writing and prints every sample as a frame
+	w, err := os.OpenFile(c.path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	for i, s := range sine(1000, 0.5) {
+		fmt.Fprintf(&b, "frame:%-4d pts:%-7d pts_time:%f\nlavfi.astats.1.DC_offset=%f\n", i, i, float64(i)/SampleRate, s)
+	}
+	w.WriteString(b.String())
+	w.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for maxLevel(c.Levels(16, 1)) < 0.8 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the samples read from the FIFO")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	path := c.path
+	c.Stop()
+	if _, err := os.Stat(path); err == nil || c.Running() {
+		t.Errorf("Expected Stop to remove the FIFO")
+	}
+}
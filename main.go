@@ -1,17 +1,33 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/isalikov/radio-record-cli/internal/api"
 	"github.com/isalikov/radio-record-cli/internal/config"
+	"github.com/isalikov/radio-record-cli/internal/hooks"
+	"github.com/isalikov/radio-record-cli/internal/metrics"
 	"github.com/isalikov/radio-record-cli/internal/player"
+	"github.com/isalikov/radio-record-cli/internal/remote"
+	"github.com/isalikov/radio-record-cli/internal/rundir"
+	"github.com/isalikov/radio-record-cli/internal/state"
+	"github.com/isalikov/radio-record-cli/internal/stats"
 	"github.com/isalikov/radio-record-cli/internal/ui"
 )
 
+// quitTimeout is how long the UI has to exit after SIGHUP or SIGTERM
+const quitTimeout = 2 * time.Second
+
 var (
 	version = "dev"
 	commit  = "none"
@@ -27,6 +43,33 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "fav":
+			os.Exit(runFav(os.Args[2:]))
+		case "status":
+			os.Exit(runStatus(os.Args[2:]))
+		}
+	}
+
+	httpAddr := flag.String("http", "", "включить HTTP API и веб-пульт на адресе host:port")
+	resume := flag.Bool("resume", false, "запустить станцию, игравшую в прошлый раз")
+	noResume := flag.Bool("no-resume", false, "начать с чистого листа: без станции и позиции курсора")
+	audioDevice := flag.String("audio-device", "", "аудиоустройство mpv на этот запуск, help — список устройств")
+	metricsAddr := flag.String("metrics", "", "отдавать только /metrics на адресе host:port, без HTTP API")
+	flag.Parse()
+
+	// Команда палитры, выполняемая после загрузки станций: radio-record play deep
+	startup := strings.Join(flag.Args(), " ")
+	if startup != "" && !ui.IsCommand(startup) {
+		fmt.Printf("Неизвестная команда: %s\n\nКоманды:\n", flag.Arg(0))
+		for _, c := range ui.Commands() {
+			fmt.Printf("  %-10s %-24s %s\n", c.Name, c.Args, c.Help)
+		}
+		os.Exit(2)
+	}
+
 	// Check if mpv is installed
 	if _, err := exec.LookPath("mpv"); err != nil {
 		fmt.Println("Ошибка: mpv не найден. Установите mpv:")
@@ -43,22 +86,156 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Private directory for the state file and mpv sockets
+	if _, err := rundir.Ensure(); err != nil {
+		fmt.Printf("Ошибка: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Sockets and orphan mpv left by instances that were killed or crashed
+	player.CleanStale()
+
 	client := api.NewClient()
 	p := player.New()
 
 	// Set volume from config
 	p.SetVolume(cfg.Volume)
 
-	model := ui.NewModel(client, p, cfg)
+	p.SetCrossfade(time.Duration(cfg.Crossfade * float64(time.Second)))
 
-	program := tea.NewProgram(model, tea.WithAltScreen())
+	// Audio output: the flag overrides the saved device for this run. A
+	// saved device that is gone (an unplugged headset) falls back to auto
+	// for this run, a mistyped flag is an error.
+	switch *audioDevice {
+	case "":
+		if err := p.CheckAudioDevice(cfg.AudioDevice); !errors.Is(err, player.ErrUnknownDevice) {
+			p.SetAudioDevice(cfg.AudioDevice)
+		}
+	case "help":
+		os.Exit(listAudioDevices(p))
+	default:
+		if err := p.CheckAudioDevice(*audioDevice); err != nil {
+			fmt.Printf("Ошибка: %v\n", err)
+			os.Exit(1)
+		}
+		p.SetAudioDevice(*audioDevice)
+	}
 
-	if _, err := program.Run(); err != nil {
-		fmt.Printf("Ошибка: %v\n", err)
-		os.Exit(1)
+	// Shared state for `radio-record status`
+	st := state.NewStore(state.DefaultPath())
+	st.Update(func(s *state.State) { s.Volume = p.Volume() })
+
+	// User commands and webhooks on player events. Failures show up as the
+	// state error, so status bars and the error hooks see them.
+	runner := hooks.New(cfg.Hooks)
+	runner.OnError = func(_ config.Hook, err error) { st.Fail(err) }
+	stopHooks := runner.Run(st)
+
+	// Listening statistics for sorting by recent and most played
+	sts := stats.Load(stats.DefaultPath())
+	stopStats := sts.Run(st)
+
+	model := ui.NewModel(client, p, cfg, st, sts)
+	switch {
+	case *noResume:
+		model.SetResume(false, false)
+	case *resume:
+		model.SetResume(true, true)
+	}
+	model.SetStartup(startup)
+
+	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithReportFocus())
+
+	// mpv must not outlive the app. The model shares the player and the
+	// visualizer with the running program, so Close stops them from here.
+	defer func() {
+		if r := recover(); r != nil {
+			model.Close()
+			panic(r)
+		}
+	}()
+
+	// Bubble Tea turns SIGINT and SIGTERM into a quit, SIGHUP comes when the
+	// terminal is closed. mpv is stopped right away in case the UI cannot
+	// finish, and the program is killed if it does not quit in time.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP, syscall.SIGTERM)
+	go func() {
+		<-signals
+		model.Close()
+		program.Quit()
+		time.Sleep(quitTimeout)
+		program.Kill()
+	}()
+
+	// Prometheus metrics are collected from the start. They are served by
+	// the HTTP API and, for scrapers without the token, on --metrics.
+	ctx, cancel := context.WithCancel(context.Background())
+	mx := metrics.New(st, p)
+	client.Observe(mx.ObserveRequest)
+	stopMetrics := mx.Run()
+	defer stopMetrics()
+	if *metricsAddr != "" {
+		go func() {
+			if err := mx.ListenAndServe(ctx, *metricsAddr); err != nil {
+				st.Fail(err)
+			}
+		}()
+	}
+
+	// Local control API and web remote
+	if cfg.HTTP.Enabled || *httpAddr != "" {
+		if cfg.HTTP.Token == "" {
+			cfg.HTTP.Token = remote.NewToken()
+			cfg.Save()
+		}
+		addr := *httpAddr
+		if addr == "" {
+			addr = cfg.HTTP.Addr
+		}
+		if addr == "" {
+			addr = config.DefaultHTTPAddr
+		}
+		server := remote.NewServer(cfg.HTTP.Token, st, ui.NewController(program), client)
+		server.Handle("/metrics", mx)
+
+		go func() {
+			if err := server.ListenAndServe(ctx, addr); err != nil {
+				st.Fail(err)
+			}
+		}()
 	}
 
-	// Save volume to config on exit
+	final, err := program.Run()
+	model.Close()
+	cancel()
+	stopHooks()
+	stopStats()
+	st.Close()
+
+	// Save the session and volume on every way out: q, Ctrl+C, SIGTERM
+	// and SIGHUP all end here with the last model
+	if m, ok := final.(ui.Model); ok {
+		m.SaveSession()
+	}
 	cfg.Volume = p.Volume()
 	cfg.Save()
+
+	if err != nil {
+		fmt.Printf("Ошибка: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// listAudioDevices prints the outputs accepted by --audio-device
+func listAudioDevices(p *player.Player) int {
+	devices, err := p.AudioDevices()
+	if err != nil {
+		fmt.Printf("Ошибка: %v\n", err)
+		return 1
+	}
+	for _, d := range devices {
+		fmt.Printf("  %-40s %s\n", d.Name, d.Description)
+	}
+	return 0
 }
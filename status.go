@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/isalikov/radio-record-cli/internal/state"
+)
+
+const defaultStatusFormat = "{station} · {artist} — {song}"
+
+// statusPollInterval is how often --follow checks the state file
+const statusPollInterval = 500 * time.Millisecond
+
+// runStatus prints what a running instance is playing, for tmux, polybar
+// and waybar. Returns the exit code.
+func runStatus(args []string) int {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	format := fs.String("format", defaultStatusFormat, "формат строки: {station} {prefix} {artist} {song} {track} {volume} {status}")
+	waybar := fs.Bool("waybar", false, "вывод в JSON для custom-модуля waybar")
+	follow := fs.Bool("follow", false, "выводить строку при каждом изменении")
+	path := fs.String("state", state.DefaultPath(), "путь к файлу состояния")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	render := func(st state.State) string {
+		if *waybar {
+			data, err := st.Waybar(*format)
+			if err != nil {
+				return "{}"
+			}
+			return string(data)
+		}
+		if !st.Playing {
+			return ""
+		}
+		return st.Format(*format)
+	}
+
+	if !*follow {
+		fmt.Println(render(readState(*path)))
+		return 0
+	}
+
+	last := ""
+	first := true
+	for {
+		line := render(readState(*path))
+		if first || line != last {
+			fmt.Println(line)
+			last = line
+			first = false
+		}
+		time.Sleep(statusPollInterval)
+	}
+}
+
+// readState returns the state of a running instance, or an empty state
+// if nothing is running
+func readState(path string) state.State {
+	st, err := state.Read(path)
+	if err != nil || !st.Alive() {
+		return state.State{}
+	}
+	return st
+}